@@ -5,23 +5,45 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
 	"github.com/programme-lv/tasks-microservice/internal/handlers"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbauditsink"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbcollectionrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbolympiadrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbreviewrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbstatsrepo"
 	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/s3blobstore"
 	"github.com/programme-lv/tasks-microservice/internal/service"
 
 	awschi "github.com/awslabs/aws-lambda-go-api-proxy/chi"
 )
 
 func main() {
-	taskService := service.NewTaskService(getDynamoDbRepo())
-	controller := handlers.NewController(taskService)
+	testFileStore := getS3TestFileStore()
+	taskRepo := service.NewExampleCheckingTaskRepo(getDynamoDbRepo(), testFileStore)
+	auditService := service.NewAuditService(getDynamoDbAuditSink(), taskRepo)
+	reviewRepo := getDynamoDbReviewRepo()
+	taskService := service.NewTaskService(taskRepo, reviewRepo, auditService)
+	reviewService := service.NewReviewService(reviewRepo, taskRepo, auditService)
+	olympiadService := service.NewOlympiadService(getDynamoDbOlympiadRepo(), taskRepo, auditService)
+	collectionService := service.NewCollectionService(getDynamoDbCollectionRepo(), taskRepo)
+	visibleInputService := service.NewVisibleInputService(taskRepo, testFileStore)
+	statisticsService := service.NewStatisticsService(getDynamoDbStatisticsRepo(), taskRepo,
+		service.NewDifficultyEstimator())
+	controller := handlers.NewController(taskService, olympiadService,
+		collectionService, visibleInputService, auditService, reviewService,
+		statisticsService, service.NewRecommendationService(taskRepo), getJwtVerifier())
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -39,12 +61,24 @@ func main() {
 	lambda.Start(handler)
 }
 
+// corsMiddleware allows cross-origin requests only from the origins
+// listed in CORS_ALLOWED_ORIGINS, separated by commas.
 func corsMiddleware(next http.Handler) http.Handler {
+	allowedOrigins := map[string]bool{}
+	for _, origin := range strings.Split(getRequiredEnv("CORS_ALLOWED_ORIGINS"), ",") {
+		allowedOrigins[strings.TrimSpace(origin)] = true
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods",
-			"GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "*")
+		w.Header().Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		if allowedOrigins[origin] {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods",
+				"GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers",
+				"Authorization, Content-Type, Accept-Language")
+		}
 
 		// Handle preflight request
 		if r.Method == http.MethodOptions {
@@ -57,17 +91,69 @@ func corsMiddleware(next http.Handler) http.Handler {
 }
 
 func getDynamoDbRepo() service.TaskRepo {
-	tableName := os.Getenv("TASKS_TABLE_NAME")
-	if tableName == "" {
-		panic("TASKS_TABLE_NAME environment variable is not set")
+	return ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getRequiredEnv("TASKS_TABLE_NAME"), getRequiredEnv("TASK_REVISIONS_TABLE_NAME"))
+}
+
+func getDynamoDbOlympiadRepo() service.OlympiadRepo {
+	return ddbolympiadrepo.NewDynamoDbOlympiadRepo(getDynamoDbClient(),
+		getRequiredEnv("OLYMPIADS_TABLE_NAME"))
+}
+
+func getDynamoDbCollectionRepo() service.CollectionRepo {
+	return ddbcollectionrepo.NewDynamoDbCollectionRepo(getDynamoDbClient(),
+		getRequiredEnv("COLLECTIONS_TABLE_NAME"))
+}
+
+func getDynamoDbReviewRepo() service.ReviewRepo {
+	return ddbreviewrepo.NewDynamoDbReviewRepo(getDynamoDbClient(),
+		getRequiredEnv("REVIEWS_TABLE_NAME"))
+}
+
+func getDynamoDbStatisticsRepo() service.StatisticsRepo {
+	return ddbstatsrepo.NewDynamoDbStatisticsRepo(getDynamoDbClient(),
+		getRequiredEnv("STATISTICS_TABLE_NAME"))
+}
+
+func getDynamoDbAuditSink() service.AuditSink {
+	return ddbauditsink.NewDynamoDbAuditSink(getDynamoDbClient(),
+		getRequiredEnv("AUDIT_TABLE_NAME"))
+}
+
+// getS3TestFileStore reads test files by their sha256 from the bucket
+// TESTS_BUCKET_NAME, below the key prefix TESTS_KEY_PREFIX if it is set.
+func getS3TestFileStore() service.BlobStore {
+	return s3blobstore.NewPrefixedS3BlobStore(s3.NewFromConfig(getAwsConfig()),
+		getRequiredEnv("TESTS_BUCKET_NAME"), os.Getenv("TESTS_KEY_PREFIX"))
+}
+
+// getJwtVerifier verifies tokens with the PEM encoded public keys
+// in JWT_PUBLIC_KEYS and, if set, the issuer in JWT_ISSUER.
+func getJwtVerifier() *auth.JwtVerifier {
+	keys, err := auth.ParsePublicKeysPem([]byte(getRequiredEnv("JWT_PUBLIC_KEYS")))
+	if err != nil {
+		panic(fmt.Sprintf("unable to parse JWT_PUBLIC_KEYS, %v", err))
 	}
+	return auth.NewJwtVerifier(keys, os.Getenv("JWT_ISSUER"))
+}
+
+func getAwsConfig() aws.Config {
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion("eu-central-1"))
 	if err != nil {
 		panic(fmt.Sprintf("unable to load SDK config, %v", err))
 	}
-	dynamoClient := dynamodb.NewFromConfig(cfg)
-	repo := ddbtaskrepo.NewDynamoDbTaskRepo(dynamoClient,
-		tableName)
-	return repo
+	return cfg
+}
+
+func getDynamoDbClient() *dynamodb.Client {
+	return dynamodb.NewFromConfig(getAwsConfig())
+}
+
+func getRequiredEnv(key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		panic(fmt.Sprintf("%s environment variable is not set", key))
+	}
+	return value
 }
@@ -4,17 +4,35 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
 	"github.com/programme-lv/tasks-microservice/internal/handlers"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbcollectionrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbolympiadrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbreviewrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbstatsrepo"
 	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/jsonlauditsink"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/s3blobstore"
 	"github.com/programme-lv/tasks-microservice/internal/service"
 )
 
-const taskTable = "ProglvTasks"
+const (
+	taskTable       = "ProglvTasks"
+	revisionTable   = "ProglvTaskRevisions"
+	olympiadTable   = "ProglvOlympiads"
+	collectionTable = "ProglvCollections"
+	statisticsTable = "ProglvTaskStatistics"
+	reviewTable     = "ProglvTaskReviews"
+	auditLogFile    = "audit.jsonl"
+	testsBucket     = "proglv-tests"
+)
 
 func main() {
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
@@ -24,10 +42,36 @@ func main() {
 	}
 	dynamodbClient := dynamodb.NewFromConfig(cfg)
 
-	repo := ddbtaskrepo.NewDynamoDbTaskRepo(dynamodbClient, taskTable)
+	testFileStore := s3blobstore.NewPrefixedS3BlobStore(s3.NewFromConfig(cfg), testsBucket,
+		os.Getenv("TESTS_KEY_PREFIX"))
+	repo := service.NewExampleCheckingTaskRepo(
+		ddbtaskrepo.NewDynamoDbTaskRepo(dynamodbClient, taskTable, revisionTable), testFileStore)
+	olympiadRepo := ddbolympiadrepo.NewDynamoDbOlympiadRepo(dynamodbClient, olympiadTable)
+	collectionRepo := ddbcollectionrepo.NewDynamoDbCollectionRepo(dynamodbClient, collectionTable)
+
+	auditService := service.NewAuditService(jsonlauditsink.NewJsonlAuditSink(auditLogFile), repo)
+
+	reviewRepo := ddbreviewrepo.NewDynamoDbReviewRepo(dynamodbClient, reviewTable)
+
+	taskService := service.NewTaskService(repo, reviewRepo, auditService)
+	reviewService := service.NewReviewService(reviewRepo, repo, auditService)
+	olympiadService := service.NewOlympiadService(olympiadRepo, repo, auditService)
+	collectionService := service.NewCollectionService(collectionRepo, repo)
+	visibleInputService := service.NewVisibleInputService(repo, testFileStore)
+	statisticsService := service.NewStatisticsService(
+		ddbstatsrepo.NewDynamoDbStatisticsRepo(dynamodbClient, statisticsTable), repo,
+		service.NewDifficultyEstimator())
+	// tokens are verified with the PEM encoded public keys in JWT_PUBLIC_KEYS;
+	// without them only anonymous requests succeed
+	jwtKeys, err := auth.ParsePublicKeysPem([]byte(os.Getenv("JWT_PUBLIC_KEYS")))
+	if err != nil {
+		panic(fmt.Sprintf("unable to parse JWT_PUBLIC_KEYS, %v", err))
+	}
+	verifier := auth.NewJwtVerifier(jwtKeys, os.Getenv("JWT_ISSUER"))
 
-	taskService := service.NewTaskService(repo)
-	controller := handlers.NewController(taskService)
+	controller := handlers.NewController(taskService, olympiadService,
+		collectionService, visibleInputService, auditService, reviewService,
+		statisticsService, service.NewRecommendationService(repo), verifier)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func diffTask(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: taskctl diff <task-id> [flags]")
+		flags.PrintDefaults()
+	}
+	from := flags.Int("from", 0, "old revision (default: the one preceding -to)")
+	to := flags.Int("to", 0, "new revision (default: the current one)")
+	context := flags.Int("context", 3, "unchanged statement lines shown around changes")
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags.Usage()
+		os.Exit(2)
+	}
+	taskId := args[0]
+	flags.Parse(args[1:])
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+	taskService := service.NewTaskService(taskRepo,
+		getReviewRepo(), getAuditService(taskRepo))
+	diff, err := taskService.DiffTask(taskId, *from, *to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(diff.Format(*context))
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+// lint validates local manifest files given as arguments, or every
+// stored manifest when no files are given.
+func lint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	checkExamples := flags.Bool("check-examples", false,
+		"compare examples linked to tests against the stored test files")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: taskctl lint [flags] [manifest.toml ...]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+
+	manifests := []ddbtaskrepo.RawManifest{}
+	if flags.NArg() > 0 {
+		for _, path := range flags.Args() {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			manifests = append(manifests, ddbtaskrepo.RawManifest{
+				TaskID:   path,
+				Manifest: string(content),
+			})
+		}
+	} else {
+		stored, err := taskRepo.ListRawManifests()
+		if err != nil {
+			return err
+		}
+		manifests = stored
+	}
+
+	var exampleService *service.ExampleService
+	if *checkExamples && flags.NArg() == 0 {
+		exampleService = service.NewExampleService(taskRepo,
+			getTestFileStore(s3.NewFromConfig(getAwsConfig())))
+	}
+
+	invalid := 0
+	for _, manifest := range manifests {
+		problems := []string{}
+		if flags.NArg() == 0 {
+			_, err := domain.NewTaskID(manifest.TaskID)
+			if err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+		for _, problem := range ddbtaskrepo.ValidateManifest(manifest.Manifest) {
+			problems = append(problems, problem.String())
+		}
+		if exampleService != nil && len(problems) == 0 {
+			mismatches, err := exampleService.VerifyExamples(manifest.TaskID)
+			if err != nil {
+				return err
+			}
+			for _, mismatch := range mismatches {
+				problems = append(problems, mismatch.String())
+			}
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		invalid++
+		for _, problem := range problems {
+			fmt.Printf("%s: %s\n", manifest.TaskID, problem)
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d manifest(s) are invalid", invalid, len(manifests))
+	}
+	return nil
+}
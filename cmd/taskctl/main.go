@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbauditsink"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbreviewrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/jsonlauditsink"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/s3blobstore"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"lint": {
+		usage: "validate manifest files or every stored manifest",
+		run:   lint,
+	},
+	"migrate": {
+		usage: "rewrite every stored manifest in the latest schema version",
+		run:   migrateManifests,
+	},
+	"render-pdf": {
+		usage: "generate a pdf statement from the markdown statement",
+		run:   renderPdf,
+	},
+	"diff": {
+		usage: "show what changed between two revisions of a task",
+		run:   diffTask,
+	},
+	"migrate-olympiads": {
+		usage: "map origin_olympiad strings onto the olympiad catalogue",
+		run:   migrateOlympiads,
+	},
+	"set-owners": {
+		usage: "replace the users allowed to edit a task",
+		run:   setTaskOwners,
+	},
+	"rename": {
+		usage: "change a task id, keeping the old id as an alias",
+		run:   renameTask,
+	},
+	"suggest-id": {
+		usage: "derive an unused task id from a task name",
+		run:   suggestTaskId,
+	},
+	"purge": {
+		usage: "permanently remove long deleted tasks and their blobs",
+		run:   purgeTasks,
+	},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	err := cmd.run(os.Args[2:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: taskctl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", name, commands[name].usage)
+	}
+}
+
+func getAwsConfig() aws.Config {
+	cfg, err := config.LoadDefaultConfig(context.TODO(),
+		config.WithRegion("eu-central-1"))
+	if err != nil {
+		panic(fmt.Sprintf("unable to load SDK config, %v", err))
+	}
+	return cfg
+}
+
+func getDynamoDbClient() *dynamodb.Client {
+	return dynamodb.NewFromConfig(getAwsConfig())
+}
+
+func getEnvOrDefault(key string, def string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// getTestFileStore reads test files by their sha256 from the bucket
+// TESTS_BUCKET_NAME, below the key prefix TESTS_KEY_PREFIX if it is set.
+func getTestFileStore(s3Client *s3.Client) service.BlobStore {
+	return s3blobstore.NewPrefixedS3BlobStore(s3Client,
+		getEnvOrDefault("TESTS_BUCKET_NAME", "proglv-tests"), os.Getenv("TESTS_KEY_PREFIX"))
+}
+
+func getReviewRepo() service.ReviewRepo {
+	return ddbreviewrepo.NewDynamoDbReviewRepo(getDynamoDbClient(),
+		getEnvOrDefault("REVIEWS_TABLE_NAME", "ProglvTaskReviews"))
+}
+
+// getAuditService records audit events in the JSONL file AUDIT_LOG_FILE
+// if it is set and in the DynamoDB audit table otherwise.
+func getAuditService(taskRepo service.TaskRepo) *service.AuditService {
+	if path := os.Getenv("AUDIT_LOG_FILE"); path != "" {
+		return service.NewAuditService(jsonlauditsink.NewJsonlAuditSink(path), taskRepo)
+	}
+	return service.NewAuditService(ddbauditsink.NewDynamoDbAuditSink(getDynamoDbClient(),
+		getEnvOrDefault("AUDIT_TABLE_NAME", "ProglvTaskAudit")), taskRepo)
+}
+
+// cliActor is the identity taskctl acts as. Running taskctl requires
+// AWS credentials for the tables, so it acts as an admin.
+func cliActor() *auth.Identity {
+	return &auth.Identity{
+		Subject: fmt.Sprintf("taskctl:%s", getEnvOrDefault("USER", "unknown")),
+		Roles:   []auth.Role{auth.RoleAdmin},
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbolympiadrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func migrateOlympiads(args []string) error {
+	flags := flag.NewFlagSet("migrate-olympiads", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only print the mapping")
+	flags.Parse(args)
+
+	db := getDynamoDbClient()
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(db,
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+	olympiadRepo := ddbolympiadrepo.NewDynamoDbOlympiadRepo(db,
+		getEnvOrDefault("OLYMPIADS_TABLE_NAME", "ProglvOlympiads"))
+	olympiadService := service.NewOlympiadService(olympiadRepo, taskRepo, getAuditService(taskRepo))
+
+	migrations, err := olympiadService.MigrateOriginOlympiads(cliActor(), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.OlympiadId == "" {
+			fmt.Printf("%s: %q -> (unmapped)\n", migration.TaskId, migration.OriginOlympiad)
+			continue
+		}
+		fmt.Printf("%s: %q -> %s\n", migration.TaskId, migration.OriginOlympiad, migration.OlympiadId)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/textdiff"
+)
+
+func migrateManifests(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only print the manifest diffs")
+	flags.Parse(args)
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+
+	migrations, err := taskRepo.MigrateManifests(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		fmt.Printf("=== %s (v%d -> v%d)\n", migration.TaskID,
+			migration.FromVersion, ddbtaskrepo.CurrentManifestSchemaVersion)
+		if *dryRun {
+			fmt.Print(textdiff.Format(textdiff.Lines(migration.Before, migration.After), 2))
+		}
+	}
+	if *dryRun {
+		fmt.Printf("%d manifest(s) would be rewritten\n", len(migrations))
+	} else {
+		fmt.Printf("%d manifest(s) rewritten\n", len(migrations))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/s3blobstore"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func purgeTasks(args []string) error {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	olderThanDays := flags.Int("older-than-days", 30, "purge tasks deleted at least this many days ago")
+	dryRun := flags.Bool("dry-run", false, "only print what would be removed")
+	flags.Parse(args)
+
+	if *olderThanDays < 0 {
+		return fmt.Errorf("-older-than-days must not be negative")
+	}
+
+	s3Client := s3.NewFromConfig(getAwsConfig())
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+	purgeService := service.NewPurgeService(taskRepo,
+		getTestFileStore(s3Client),
+		s3blobstore.NewS3BlobStore(s3Client, getEnvOrDefault("PUBLIC_BUCKET_NAME", "proglv-public")),
+		getAuditService(taskRepo))
+
+	deletedBefore := time.Now().AddDate(0, 0, -*olderThanDays)
+	result, err := purgeService.PurgeDeletedTasks(cliActor(), deletedBefore, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, taskId := range result.TaskIds {
+		fmt.Printf("task %s\n", taskId)
+	}
+	for _, key := range result.TestFileKeys {
+		fmt.Printf("test file %s\n", key)
+	}
+	for _, key := range result.PublicFileKeys {
+		fmt.Printf("public file %s\n", key)
+	}
+	if *dryRun {
+		fmt.Printf("dry run: %d tasks would be purged\n", len(result.TaskIds))
+	} else {
+		fmt.Printf("purged %d tasks\n", len(result.TaskIds))
+	}
+	return nil
+}
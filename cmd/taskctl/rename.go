@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func renameTask(args []string) error {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: taskctl rename <task-id> <new-task-id>")
+		os.Exit(2)
+	}
+	taskId, newTaskId := args[0], args[1]
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+	taskService := service.NewTaskService(taskRepo,
+		getReviewRepo(), getAuditService(taskRepo))
+
+	task, err := taskService.RenameTask(cliActor(), taskId, newTaskId)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s -> %s (aliases: %v)\n", taskId, task.GetId(), task.GetAliasIds())
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/programme-lv/tasks-microservice/internal/rendering"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/repositories/s3blobstore"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+const publicBucketCloudFrontHost = "dvhk4hiwp1rmf.cloudfront.net"
+
+func renderPdf(args []string) error {
+	flags := flag.NewFlagSet("render-pdf", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: taskctl render-pdf <task-id> [flags]")
+		flags.PrintDefaults()
+	}
+	lang := flags.String("lang", "lv", "statement language")
+	replace := flags.Bool("replace", false, "replace an existing pdf statement in that language")
+	renderer := flags.String("renderer",
+		"chromium --headless --no-pdf-header-footer --virtual-time-budget=10000 "+
+			"--print-to-pdf={output} {input}",
+		"command that renders the HTML file {input} to the PDF file {output}, "+
+			"or reads HTML from stdin and writes PDF to stdout without them")
+	katexDir := flags.String("katex", getEnvOrDefault("KATEX_DIST_DIR", "node_modules/katex/dist"),
+		"dist directory of the katex npm package")
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags.Usage()
+		os.Exit(2)
+	}
+	taskId := args[0]
+	flags.Parse(args[1:])
+
+	katex, err := rendering.LoadKatexAssets(*katexDir)
+	if err != nil {
+		return err
+	}
+
+	blobStore := s3blobstore.NewS3BlobStore(s3.NewFromConfig(getAwsConfig()),
+		getEnvOrDefault("PUBLIC_BUCKET_NAME", "proglv-public"))
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+
+	statementRenderer := rendering.NewStatementRenderer(func(objKey string) string {
+		return fmt.Sprintf("https://%s/%s", publicBucketCloudFrontHost, objKey)
+	})
+	pdfService := service.NewPdfStatementService(taskRepo, blobStore,
+		rendering.NewHtmlStatementRenderer(statementRenderer), katex,
+		rendering.NewCommandPdfRenderer(strings.Fields(*renderer)...),
+		getAuditService(taskRepo))
+
+	sha256, err := pdfService.GeneratePdfStatement(cliActor(), taskId, *lang, *replace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: %s pdf statement %s\n", taskId, *lang, sha256)
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func setTaskOwners(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "usage: taskctl set-owners <task-id> [<user-id>...]")
+		os.Exit(2)
+	}
+	taskId, ownerIds := args[0], args[1:]
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+	taskService := service.NewTaskService(taskRepo,
+		getReviewRepo(), getAuditService(taskRepo))
+
+	task, err := taskService.SetTaskOwners(cliActor(), taskId, ownerIds)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: owners %v\n", task.GetId(), task.GetOwnerIds())
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/repositories/ddbtaskrepo"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func suggestTaskId(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: taskctl suggest-id <task full name>")
+		os.Exit(2)
+	}
+
+	taskRepo := ddbtaskrepo.NewDynamoDbTaskRepo(getDynamoDbClient(),
+		getEnvOrDefault("TASKS_TABLE_NAME", "ProglvTasks"),
+		getEnvOrDefault("TASK_REVISIONS_TABLE_NAME", "ProglvTaskRevisions"))
+	taskService := service.NewTaskService(taskRepo,
+		getReviewRepo(), getAuditService(taskRepo))
+
+	id, err := taskService.SuggestTaskId(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+	return nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import "context"
+
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleAuthor   Role = "author"
+	RoleReviewer Role = "reviewer"
+	RoleAdmin    Role = "admin"
+
+	// RoleJudge is held by the judge's service account, which reports
+	// submission statistics. It is not one of the roles the token issuer
+	// has granted so far: until the issuer adds "judge" to the roles claim
+	// of the judge's tokens, only admins can report statistics.
+	RoleJudge Role = "judge"
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	Subject string // user id from the token's "sub" claim
+	Roles   []Role
+}
+
+// HasRole reports whether the identity has the role. Every authenticated
+// caller is a viewer and admins are considered to have every role.
+func (i *Identity) HasRole(role Role) bool {
+	if i == nil {
+		return false
+	}
+	if role == RoleViewer {
+		return true
+	}
+	for _, r := range i.Roles {
+		if r == role || r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type identityCtxKey struct{}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, identity)
+}
+
+// IdentityFromContext returns the caller identity, or nil for
+// anonymous requests.
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityCtxKey{}).(*Identity)
+	return identity
+}
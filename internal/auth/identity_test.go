@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIdentityHasRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *Identity
+		role     Role
+		want     bool
+	}{
+		{name: "anonymous", identity: nil, role: RoleViewer, want: false},
+		{name: "every caller is a viewer", identity: &Identity{Subject: "u"}, role: RoleViewer, want: true},
+		{name: "granted role", identity: &Identity{Subject: "u", Roles: []Role{RoleAuthor}},
+			role: RoleAuthor, want: true},
+		{name: "other role", identity: &Identity{Subject: "u", Roles: []Role{RoleAuthor}},
+			role: RoleReviewer, want: false},
+		{name: "admin has every role", identity: &Identity{Subject: "u", Roles: []Role{RoleAdmin}},
+			role: RoleReviewer, want: true},
+		{name: "unknown roles are ignored", identity: &Identity{Subject: "u", Roles: []Role{"root"}},
+			role: RoleAdmin, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.identity.HasRole(tt.role); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentityFromContext(t *testing.T) {
+	if IdentityFromContext(context.Background()) != nil {
+		t.Errorf("got an identity without one in the context")
+	}
+	identity := &Identity{Subject: "u"}
+	if IdentityFromContext(WithIdentity(context.Background(), identity)) != identity {
+		t.Errorf("got a different identity than the one in the context")
+	}
+}
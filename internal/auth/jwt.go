@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// JwtVerifier verifies bearer tokens offline against configured public
+// keys. Several keys may be configured to allow key rotation.
+type JwtVerifier struct {
+	keys   []interface{}
+	issuer string
+}
+
+func NewJwtVerifier(keys []interface{}, issuer string) *JwtVerifier {
+	return &JwtVerifier{keys: keys, issuer: issuer}
+}
+
+// Verify checks the token's signature, expiry and issuer
+// and returns the identity it carries.
+func (v *JwtVerifier) Verify(token string) (*Identity, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{
+			"RS256", "RS384", "RS512",
+			"PS256", "PS384", "PS512",
+			"ES256", "ES384", "ES512",
+			"EdDSA",
+		}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+
+	err := errors.New("no verification keys configured")
+	for _, key := range v.keys {
+		c := claims{}
+		_, err = jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+			return key, nil
+		}, options...)
+		if err != nil {
+			continue
+		}
+
+		if c.Subject == "" {
+			return nil, errors.New("token has no subject")
+		}
+		identity := &Identity{Subject: c.Subject, Roles: []Role{}}
+		for _, role := range c.Roles {
+			identity.Roles = append(identity.Roles, Role(role))
+		}
+		return identity, nil
+	}
+	return nil, fmt.Errorf("invalid token: %w", err)
+}
+
+// ParsePublicKeysPem parses every RSA, ECDSA or Ed25519 public key
+// in a PEM bundle.
+func ParsePublicKeysPem(bundle []byte) ([]interface{}, error) {
+	keys := []interface{}{}
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+		encoded := pem.EncodeToMemory(block)
+
+		if key, err := jwt.ParseRSAPublicKeyFromPEM(encoded); err == nil {
+			keys = append(keys, key)
+		} else if key, err := jwt.ParseECPublicKeyFromPEM(encoded); err == nil {
+			keys = append(keys, key)
+		} else if key, err := jwt.ParseEdPublicKeyFromPEM(encoded); err == nil {
+			keys = append(keys, key)
+		} else {
+			return nil, fmt.Errorf("unsupported public key %q", block.Type)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signToken(t *testing.T, key ed25519.PrivateKey, c jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, c).SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestJwtVerifierVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	rotatedKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "user-1",
+			"iss":   "https://auth.programme.lv",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"author", "reviewer"},
+		}
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		c := valid()
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return c
+	}
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		want     *Identity
+		wantErr  string
+		verifier *JwtVerifier
+	}{
+		{
+			name:  "valid",
+			token: signToken(t, privateKey, valid()),
+			want:  &Identity{Subject: "user-1", Roles: []Role{RoleAuthor, RoleReviewer}},
+		},
+		{
+			name:  "without roles",
+			token: signToken(t, privateKey, with("roles", nil)),
+			want:  &Identity{Subject: "user-1", Roles: []Role{}},
+		},
+		{
+			name:     "second configured key",
+			token:    signToken(t, privateKey, valid()),
+			verifier: NewJwtVerifier([]interface{}{rotatedKey, publicKey}, "https://auth.programme.lv"),
+			want:     &Identity{Subject: "user-1", Roles: []Role{RoleAuthor, RoleReviewer}},
+		},
+		{
+			name:    "expired",
+			token:   signToken(t, privateKey, with("exp", time.Now().Add(-time.Minute).Unix())),
+			wantErr: "invalid token",
+		},
+		{
+			name:    "without expiry",
+			token:   signToken(t, privateKey, with("exp", nil)),
+			wantErr: "invalid token",
+		},
+		{
+			name:    "other issuer",
+			token:   signToken(t, privateKey, with("iss", "https://evil.example")),
+			wantErr: "invalid token",
+		},
+		{
+			name:    "unknown key",
+			token:   signToken(t, otherKey, valid()),
+			wantErr: "invalid token",
+		},
+		{
+			name:    "symmetric algorithm",
+			token:   hmacToken,
+			wantErr: "invalid token",
+		},
+		{
+			name:    "without subject",
+			token:   signToken(t, privateKey, with("sub", nil)),
+			wantErr: "token has no subject",
+		},
+		{
+			name:    "malformed",
+			token:   "not.a.token",
+			wantErr: "invalid token",
+		},
+		{
+			name:     "no keys configured",
+			token:    signToken(t, privateKey, valid()),
+			verifier: NewJwtVerifier([]interface{}{}, ""),
+			wantErr:  "no verification keys configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := tt.verifier
+			if verifier == nil {
+				verifier = NewJwtVerifier([]interface{}{publicKey}, "https://auth.programme.lv")
+			}
+			identity, err := verifier.Verify(tt.token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(identity, tt.want) {
+				t.Errorf("got identity %+v, want %+v", identity, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeysPem(t *testing.T) {
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	encode := func(key interface{}) string {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			t.Fatalf("failed to marshal key: %v", err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+
+	tests := []struct {
+		name     string
+		bundle   string
+		wantKeys int
+		wantErr  bool
+	}{
+		{name: "empty", bundle: "", wantKeys: 0},
+		{name: "one key", bundle: encode(edKey), wantKeys: 1},
+		{name: "rotation bundle", bundle: encode(edKey) + encode(&ecKey.PublicKey), wantKeys: 2},
+		{name: "not a public key", bundle: string(pem.EncodeToMemory(
+			&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")})), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParsePublicKeysPem([]byte(tt.bundle))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(keys) != tt.wantKeys {
+				t.Errorf("got %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+// GetAliasIds returns the former ids of the task. Links and submissions
+// that use a former id still resolve to the task.
+func (t *Task) GetAliasIds() []string {
+	return t.aliasIds
+}
+
+func (t *Task) SetAliasIds(aliasIds []string) {
+	t.aliasIds = aliasIds
+}
+
+func (t *Task) HasAlias(id string) bool {
+	for _, aliasId := range t.aliasIds {
+		if aliasId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Rename changes the id of the task and keeps the old id as an alias.
+// Renaming a task back to one of its aliases drops that alias.
+func (t *Task) Rename(newId string) error {
+	taskId, err := NewTaskID(newId)
+	if err != nil {
+		return err
+	}
+	if taskId == t.id {
+		return errorTaskIdUnchanged(newId)
+	}
+
+	aliasIds := []string{}
+	for _, aliasId := range t.aliasIds {
+		if aliasId != newId {
+			aliasIds = append(aliasIds, aliasId)
+		}
+	}
+	t.aliasIds = append(aliasIds, t.id.String())
+	t.id = taskId
+	return nil
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type AuditAction string
+
+// Tasks are not created through the service, so there is no create
+// action yet.
+const (
+	AuditActionUpdate     AuditAction = "update"
+	AuditActionPublish    AuditAction = "publish"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionBlobUpload AuditAction = "blob_upload"
+)
+
+// AuditEvent records who changed a task, when and how.
+type AuditEvent struct {
+	EventId     string // sorts chronologically within a task
+	Action      AuditAction
+	Actor       string // subject of the caller identity
+	Timestamp   time.Time
+	TaskId      string
+	OldRevision int
+	NewRevision int
+	Changes     []FieldChange
+	BlobKey     string // object key of the uploaded blob, if any
+}
+
+// MaxAuditValueBytes bounds the size of a field value in an audit event,
+// so that an event with every field changed still fits in one stored item.
+const MaxAuditValueBytes = 4096
+
+// FieldChange is a changed task field with its JSON encoded values.
+// Old is empty for added fields and New for removed ones. Values longer
+// than MaxAuditValueBytes are replaced by a JSON string summarizing them.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// TaskFields returns the task's fields as JSON encoded values keyed by
+// field name. Comparing the fields before and after a change with
+// DiffTaskFields yields a field-level diff.
+func TaskFields(t *Task) (map[string]string, error) {
+	if t == nil {
+		return map[string]string{}, nil
+	}
+
+	fields := map[string]interface{}{
+		"task_full_name":          t.taskFullName,
+		"state":                   t.state,
+		"publish_at":              t.publishAt,
+		"owner_ids":               t.ownerIds,
+		"alias_ids":               t.aliasIds,
+		"deleted_at":              t.deletedAt,
+		"memory_limit_megabytes":  t.memoryLimitMBytes,
+		"cpu_time_limit_seconds":  t.cpuTimeLimitSecs,
+		"wall_time_limit_seconds": t.wallTimeLimitSecs,
+		"stack_limit_megabytes":   t.stackLimitMBytes,
+		"limit_overrides":         t.limitOverrides,
+		"difficulty":              t.difficulty,
+		"origin_olympiad":         t.originOlympiad,
+		"origin_olympiad_id":      t.originOlympiadId,
+		"problem_tags":            t.problemTags,
+		"pdf_statements":          t.pdfStatements,
+		"md_statements":           t.mdStatements,
+		"img_uuid_to_obj_key":     t.ImgUuidToObjKey,
+		"examples":                t.examples,
+		"illustration_img":        t.illustrationImgObjKey,
+		"origin_notes":            t.originNotes,
+		"visible_inputs":          t.GetVisInpStInputs(),
+		"tests":                   t.tests,
+		"test_groups":             t.testGroups,
+		"subtasks":                t.subtasks,
+		"evaluation":              t.evaluation,
+	}
+
+	res := make(map[string]string, len(fields))
+	for name, value := range fields {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode task field %s: %w", name, err)
+		}
+		res[name] = string(encoded)
+	}
+	return res, nil
+}
+
+// DiffTaskFields lists the fields whose values differ, sorted by name.
+func DiffTaskFields(before map[string]string, after map[string]string) []FieldChange {
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	changes := []FieldChange{}
+	for name := range names {
+		if before[name] != after[name] {
+			changes = append(changes, FieldChange{
+				Field: name,
+				Old:   summarizeAuditValue(before[name]),
+				New:   summarizeAuditValue(after[name]),
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+// summarizeAuditValue replaces a value longer than MaxAuditValueBytes
+// by a JSON string with its size and SHA-256 hash.
+func summarizeAuditValue(value string) string {
+	if len(value) <= MaxAuditValueBytes {
+		return value
+	}
+	hash := sha256.Sum256([]byte(value))
+	summary, _ := json.Marshal(fmt.Sprintf("%d bytes, sha256 %s",
+		len(value), hex.EncodeToString(hash[:])))
+	return string(summary)
+}
@@ -0,0 +1,75 @@
+package domain
+
+type Collection struct {
+	id string
+
+	title       string
+	description string
+	owner       string
+	taskIds     []string // ordered
+}
+
+func NewCollection(id string, title string, owner string) (*Collection, error) {
+	collection := &Collection{
+		id:          id,
+		title:       "",
+		description: "",
+		owner:       owner,
+		taskIds:     []string{},
+	}
+
+	err := collection.SetTitle(title)
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+func (c *Collection) GetId() string {
+	return c.id
+}
+
+func (c *Collection) GetTitle() string {
+	return c.title
+}
+
+func (c *Collection) SetTitle(title string) error {
+	if title == "" {
+		return errorCollectionTitleIsRequired()
+	}
+	c.title = title
+	return nil
+}
+
+func (c *Collection) GetDescription() string {
+	return c.description
+}
+
+func (c *Collection) SetDescription(description string) {
+	c.description = description
+}
+
+func (c *Collection) GetOwner() string {
+	return c.owner
+}
+
+func (c *Collection) GetTaskIds() []string {
+	return c.taskIds
+}
+
+// SetTaskIds replaces the ordered list of tasks in the collection.
+func (c *Collection) SetTaskIds(taskIds []string) error {
+	seen := map[string]bool{}
+	for _, taskId := range taskIds {
+		if taskId == "" {
+			return errorCollectionTaskIdIsRequired()
+		}
+		if seen[taskId] {
+			return errorCollectionTaskIdIsDuplicated(taskId)
+		}
+		seen[taskId] = true
+	}
+	c.taskIds = taskIds
+	return nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// GetDeletedAt returns when the task was deleted, or nil if it was not.
+// Deleted tasks are kept so that they can be restored until purged.
+func (t *Task) GetDeletedAt() *time.Time {
+	return t.deletedAt
+}
+
+func (t *Task) IsDeleted() bool {
+	return t.deletedAt != nil
+}
+
+func (t *Task) SetDeletedAt(deletedAt *time.Time) {
+	t.deletedAt = deletedAt
+}
+
+func (t *Task) Delete(at time.Time) error {
+	if t.IsDeleted() {
+		return errorTaskIsAlreadyDeleted()
+	}
+	t.deletedAt = &at
+	return nil
+}
+
+func (t *Task) Restore() error {
+	if !t.IsDeleted() {
+		return errorTaskIsNotDeleted()
+	}
+	t.deletedAt = nil
+	return nil
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type DomainError struct {
 	StatusCode int
@@ -12,7 +15,11 @@ func (err *DomainError) Error() string {
 }
 
 const (
-	StateConflictErrorCode = 409
+	UnauthorizedErrorCode        = 401
+	ForbiddenErrorCode           = 403
+	NotFoundErrorCode            = 404
+	StateConflictErrorCode       = 409
+	UnprocessableEntityErrorCode = 422
 )
 
 func errorTaskFullNameIsRequired() *DomainError {
@@ -54,3 +61,533 @@ func errorTestIdMustBePositive() *DomainError {
 		},
 	}
 }
+
+func errorOlympiadIdIsRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("olympiad id is required"),
+			"lv": fmt.Errorf("olimpiādes id ir obligāts"),
+		},
+	}
+}
+
+func errorOlympiadYearMustBePositive() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("olympiad year must be positive"),
+			"lv": fmt.Errorf("olimpiādes gadam jābūt pozitīvam"),
+		},
+	}
+}
+
+func ErrorOlympiadNotFound(id string) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("olympiad %s not found", id),
+			"lv": fmt.Errorf("olimpiāde %s nav atrasta", id),
+		},
+	}
+}
+
+func errorCollectionTitleIsRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("collection title is required"),
+			"lv": fmt.Errorf("kolekcijas nosaukums ir obligāts"),
+		},
+	}
+}
+
+func errorCollectionTaskIdIsRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("collection task id is required"),
+			"lv": fmt.Errorf("kolekcijas uzdevuma id ir obligāts"),
+		},
+	}
+}
+
+func errorCollectionTaskIdIsDuplicated(taskId string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task %s appears in the collection more than once", taskId),
+			"lv": fmt.Errorf("uzdevums %s kolekcijā atkārtojas", taskId),
+		},
+	}
+}
+
+func ErrorCollectionNotFound(id string) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("collection %s not found", id),
+			"lv": fmt.Errorf("kolekcija %s nav atrasta", id),
+		},
+	}
+}
+
+func ErrorInvalidManifest(problems []string) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("invalid task manifest: %s", strings.Join(problems, "; ")),
+			"lv": fmt.Errorf("nederīgs uzdevuma manifests: %s", strings.Join(problems, "; ")),
+		},
+	}
+}
+
+func errorMemoryLimitMustBePositive() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("memory limit must be positive"),
+			"lv": fmt.Errorf("atmiņas ierobežojumam jābūt pozitīvam"),
+		},
+	}
+}
+
+func errorCpuTimeLimitMustBePositive() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("cpu time limit must be positive"),
+			"lv": fmt.Errorf("procesora laika ierobežojumam jābūt pozitīvam"),
+		},
+	}
+}
+
+func ErrorTaskNotFound(id string) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task %s not found", id),
+			"lv": fmt.Errorf("uzdevums %s nav atrasts", id),
+		},
+	}
+}
+
+func ErrorTaskRevisionNotFound(id string, revision int) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("revision %d of task %s not found", revision, id),
+			"lv": fmt.Errorf("uzdevuma %s versija %d nav atrasta", id, revision),
+		},
+	}
+}
+
+func ErrorTaskModifiedConcurrently(id string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task %s was modified concurrently", id),
+			"lv": fmt.Errorf("uzdevums %s tika vienlaicīgi mainīts", id),
+		},
+	}
+}
+
+func ErrorMarkdownStatementNotFound(language string) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task has no markdown statement in language %q", language),
+			"lv": fmt.Errorf("uzdevumam nav markdown formulējuma valodā %q", language),
+		},
+	}
+}
+
+func ErrorPdfStatementExists(language string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task already has a pdf statement in language %q", language),
+			"lv": fmt.Errorf("uzdevumam jau ir pdf formulējums valodā %q", language),
+		},
+	}
+}
+
+func ErrorSubtaskHasNoVisibleInputs(subtask int) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("subtask %d has no visible inputs", subtask),
+			"lv": fmt.Errorf("apakšuzdevumam %d nav redzamu ievaddatu", subtask),
+		},
+	}
+}
+
+func errorWallTimeLimitOutOfRange() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("wall time limit must be between 0 and %g seconds", maxWallTimeSecs),
+			"lv": fmt.Errorf("kopējā laika ierobežojumam jābūt starp 0 un %g sekundēm", maxWallTimeSecs),
+		},
+	}
+}
+
+func errorStackLimitMustNotBeNegative() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("stack limit must not be negative"),
+			"lv": fmt.Errorf("steka ierobežojums nedrīkst būt negatīvs"),
+		},
+	}
+}
+
+func errorLimitMultiplierOutOfRange() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("limit multiplier must be between 0 and %g", maxLimitMultiplier),
+			"lv": fmt.Errorf("ierobežojuma reizinātājam jābūt starp 0 un %g", maxLimitMultiplier),
+		},
+	}
+}
+
+func errorUnknownEvaluationType(evaluationType string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("unknown evaluation type %q", evaluationType),
+			"lv": fmt.Errorf("nezināms vērtēšanas veids %q", evaluationType),
+		},
+	}
+}
+
+func errorFloatToleranceMustBePositive() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("float tolerance must be positive"),
+			"lv": fmt.Errorf("skaitļu salīdzināšanas precizitātei jābūt pozitīvai"),
+		},
+	}
+}
+
+func errorCheckerIsRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("checker is required"),
+			"lv": fmt.Errorf("pārbaudītājs ir obligāts"),
+		},
+	}
+}
+
+func errorInteractorIsRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("interactor is required"),
+			"lv": fmt.Errorf("interaktors ir obligāts"),
+		},
+	}
+}
+
+func errorIncompleteSourceRef() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("source file language and sha256 are required"),
+			"lv": fmt.Errorf("pirmkoda faila valoda un sha256 ir obligāti"),
+		},
+	}
+}
+
+func errorUnknownScoringPolicy(policy string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("unknown scoring policy %q", policy),
+			"lv": fmt.Errorf("nezināma vērtēšanas politika %q", policy),
+		},
+	}
+}
+
+func ErrorTaskIsNotOutputOnly() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task is not output-only"),
+			"lv": fmt.Errorf("uzdevums nav tikai izvaddatu uzdevums"),
+		},
+	}
+}
+
+func errorUnknownTaskState(state string) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("unknown task state %q", state),
+			"lv": fmt.Errorf("nezināms uzdevuma stāvoklis %q", state),
+		},
+	}
+}
+
+func errorInvalidStateTransition(from string, to string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task cannot move from %q to %q", from, to),
+			"lv": fmt.Errorf("uzdevumu nevar pārvietot no %q uz %q", from, to),
+		},
+	}
+}
+
+func ErrorAuthenticationRequired() *DomainError {
+	return &DomainError{
+		StatusCode: UnauthorizedErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("authentication is required"),
+			"lv": fmt.Errorf("nepieciešama autentifikācija"),
+		},
+	}
+}
+
+func ErrorInvalidToken() *DomainError {
+	return &DomainError{
+		StatusCode: UnauthorizedErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("invalid or expired access token"),
+			"lv": fmt.Errorf("nederīgs vai novecojis piekļuves žetons"),
+		},
+	}
+}
+
+func ErrorForbidden() *DomainError {
+	return &DomainError{
+		StatusCode: ForbiddenErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("you are not allowed to do this"),
+			"lv": fmt.Errorf("jums nav atļauts to darīt"),
+		},
+	}
+}
+
+func ErrorReviewNotFound(id string) *DomainError {
+	return &DomainError{
+		StatusCode: NotFoundErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("review %s not found", id),
+			"lv": fmt.Errorf("recenzija %s nav atrasta", id),
+		},
+	}
+}
+
+func ErrorReviewApprovalRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("the task revision must be approved in a review before publishing"),
+			"lv": fmt.Errorf("pirms publicēšanas uzdevuma versijai jābūt apstiprinātai recenzijā"),
+		},
+	}
+}
+
+func ErrorReviewSubmissionRequired() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("tasks enter review by being submitted for review"),
+			"lv": fmt.Errorf("uzdevumi nonāk recenzēšanā, tos iesniedzot recenzijai"),
+		},
+	}
+}
+
+func errorReviewIsClosed() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("review is already closed"),
+			"lv": fmt.Errorf("recenzija jau ir slēgta"),
+		},
+	}
+}
+
+func errorReviewCommentIsEmpty() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("comment must not be empty"),
+			"lv": fmt.Errorf("komentārs nedrīkst būt tukšs"),
+		},
+	}
+}
+
+func errorInvalidReviewAnchor() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("comment must refer to an existing statement section or test"),
+			"lv": fmt.Errorf("komentāram jāattiecas uz esošu formulējuma sadaļu vai testu"),
+		},
+	}
+}
+
+func errorUnknownReviewDecision(decision string) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("unknown review decision %q", decision),
+			"lv": fmt.Errorf("nezināms recenzijas lēmums %q", decision),
+		},
+	}
+}
+
+func errorCannotReviewOwnSubmission() *DomainError {
+	return &DomainError{
+		StatusCode: ForbiddenErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("you cannot review your own submission"),
+			"lv": fmt.Errorf("jūs nevarat recenzēt paša iesniegto"),
+		},
+	}
+}
+
+func errorTaskIsAlreadyDeleted() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task is already deleted"),
+			"lv": fmt.Errorf("uzdevums jau ir dzēsts"),
+		},
+	}
+}
+
+func errorTaskIsNotDeleted() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task is not deleted"),
+			"lv": fmt.Errorf("uzdevums nav dzēsts"),
+		},
+	}
+}
+
+func errorTaskIdUnchanged(id string) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task is already called %s", id),
+			"lv": fmt.Errorf("uzdevumam jau ir identifikators %s", id),
+		},
+	}
+}
+
+func ErrorTaskIdTaken(id string) *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task id %s is already in use", id),
+			"lv": fmt.Errorf("uzdevuma identifikators %s jau ir aizņemts", id),
+		},
+	}
+}
+
+func errorTaskIdLength(min int, max int) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task id must be between %d and %d characters long", min, max),
+			"lv": fmt.Errorf("uzdevuma identifikatora garumam jābūt no %d līdz %d simboliem", min, max),
+		},
+	}
+}
+
+func errorTaskIdInvalidCharacter(r rune) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task id may contain only lowercase latin letters, digits and hyphens, not %q", r),
+			"lv": fmt.Errorf("uzdevuma identifikators drīkst saturēt tikai mazos latīņu burtus, ciparus un defises, nevis %q", r),
+		},
+	}
+}
+
+func errorTaskIdMisplacedHyphen() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task id must not start or end with a hyphen or contain consecutive hyphens"),
+			"lv": fmt.Errorf("uzdevuma identifikators nedrīkst sākties vai beigties ar defisi vai saturēt vairākas defises pēc kārtas"),
+		},
+	}
+}
+
+func errorProblemTagIsEmpty() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("problem tag must not be empty"),
+			"lv": fmt.Errorf("uzdevuma birka nedrīkst būt tukša"),
+		},
+	}
+}
+
+func ErrorTaskBatchAborted() *DomainError {
+	return &DomainError{
+		StatusCode: StateConflictErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("task was not saved because another task in the same batch failed"),
+			"lv": fmt.Errorf("uzdevums netika saglabāts, jo neizdevās saglabāt citu tās pašas paketes uzdevumu"),
+		},
+	}
+}
+
+func ErrorTaskBatchTooLarge(max int) *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("a batch may update at most %d tasks", max),
+			"lv": fmt.Errorf("paketē var atjaunināt ne vairāk kā %d uzdevumus", max),
+		},
+	}
+}
+
+func ErrorTaskBatchMixesStateAndEdits() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("a batch may not both edit a task and change its state"),
+			"lv": fmt.Errorf("paketē nevar vienlaikus labot uzdevumu un mainīt tā stāvokli"),
+		},
+	}
+}
+
+func errorStatisticsCountIsNegative() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("attempts and solves must not be negative"),
+			"lv": fmt.Errorf("mēģinājumu un atrisinājumu skaits nedrīkst būt negatīvs"),
+		},
+	}
+}
+
+func errorSolvesExceedAttempts() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("solves must not exceed attempts"),
+			"lv": fmt.Errorf("atrisinājumu skaits nedrīkst pārsniegt mēģinājumu skaitu"),
+		},
+	}
+}
+
+func errorAverageScoreOutOfRange() *DomainError {
+	return &DomainError{
+		StatusCode: UnprocessableEntityErrorCode,
+		I18NErrors: map[string]error{
+			"en": fmt.Errorf("average best score must be between 0 and 100"),
+			"lv": fmt.Errorf("vidējam labākajam rezultātam jābūt starp 0 un 100"),
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package domain
+
+type EvaluationType string
+
+const (
+	EvaluationExact       EvaluationType = "exact"       // byte-for-byte comparison
+	EvaluationToken       EvaluationType = "token"       // whitespace-insensitive comparison
+	EvaluationFloat       EvaluationType = "float"       // numbers compared with a tolerance
+	EvaluationChecker     EvaluationType = "checker"     // custom checker program
+	EvaluationInteractive EvaluationType = "interactive" // interactor talks to the submission
+	EvaluationOutputOnly  EvaluationType = "output_only" // contestants submit output files
+)
+
+var evaluationTypes = []EvaluationType{
+	EvaluationExact, EvaluationToken, EvaluationFloat,
+	EvaluationChecker, EvaluationInteractive, EvaluationOutputOnly,
+}
+
+// SourceRef references a source file stored as a blob.
+type SourceRef struct {
+	Language string // programming language, e.g. "cpp17"
+	FileName string
+	Sha256   string
+}
+
+type Evaluation struct {
+	Type           EvaluationType
+	FloatTolerance float64 // only for EvaluationFloat
+
+	Checker    *SourceRef // required for EvaluationChecker, optional for output-only
+	Interactor *SourceRef // required for EvaluationInteractive
+
+	// Graders are files compiled together with submissions,
+	// keyed by the programming language of the submission.
+	Graders map[string][]SourceRef
+}
+
+func DefaultEvaluation() Evaluation {
+	return Evaluation{Type: EvaluationExact, Graders: map[string][]SourceRef{}}
+}
+
+func (t *Task) GetEvaluation() Evaluation {
+	return t.evaluation
+}
+
+// IsOutputOnly reports whether contestants submit output files instead
+// of programs. Output-only tasks have downloadable inputs and no time limits.
+func (t *Task) IsOutputOnly() bool {
+	return t.evaluation.Type == EvaluationOutputOnly
+}
+
+func (t *Task) SetEvaluation(evaluation Evaluation) error {
+	valid := false
+	for _, evaluationType := range evaluationTypes {
+		if evaluation.Type == evaluationType {
+			valid = true
+		}
+	}
+	if !valid {
+		return errorUnknownEvaluationType(string(evaluation.Type))
+	}
+
+	if evaluation.Type == EvaluationFloat && evaluation.FloatTolerance <= 0 {
+		return errorFloatToleranceMustBePositive()
+	}
+	if evaluation.Type == EvaluationChecker && evaluation.Checker == nil {
+		return errorCheckerIsRequired()
+	}
+	if evaluation.Type == EvaluationInteractive && evaluation.Interactor == nil {
+		return errorInteractorIsRequired()
+	}
+
+	refs := []*SourceRef{evaluation.Checker, evaluation.Interactor}
+	for _, graders := range evaluation.Graders {
+		for i := range graders {
+			refs = append(refs, &graders[i])
+		}
+	}
+	for _, ref := range refs {
+		if ref != nil && (ref.Language == "" || ref.Sha256 == "") {
+			return errorIncompleteSourceRef()
+		}
+	}
+
+	if evaluation.Graders == nil {
+		evaluation.Graders = map[string][]SourceRef{}
+	}
+	t.evaluation = evaluation
+	return nil
+}
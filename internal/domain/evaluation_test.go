@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetEvaluation(t *testing.T) {
+	checker := &SourceRef{Language: "cpp17", FileName: "checker.cpp", Sha256: "abc"}
+
+	tests := []struct {
+		name       string
+		evaluation Evaluation
+		wantErr    error
+	}{
+		{
+			name:       "exact",
+			evaluation: Evaluation{Type: EvaluationExact},
+		},
+		{
+			name:       "unknown type",
+			evaluation: Evaluation{Type: "fuzzy"},
+			wantErr:    errorUnknownEvaluationType("fuzzy"),
+		},
+		{
+			name:       "float with tolerance",
+			evaluation: Evaluation{Type: EvaluationFloat, FloatTolerance: 1e-6},
+		},
+		{
+			name:       "float without tolerance",
+			evaluation: Evaluation{Type: EvaluationFloat},
+			wantErr:    errorFloatToleranceMustBePositive(),
+		},
+		{
+			name:       "checker",
+			evaluation: Evaluation{Type: EvaluationChecker, Checker: checker},
+		},
+		{
+			name:       "checker missing",
+			evaluation: Evaluation{Type: EvaluationChecker},
+			wantErr:    errorCheckerIsRequired(),
+		},
+		{
+			name:       "interactor missing",
+			evaluation: Evaluation{Type: EvaluationInteractive},
+			wantErr:    errorInteractorIsRequired(),
+		},
+		{
+			name:       "output-only with optional checker",
+			evaluation: Evaluation{Type: EvaluationOutputOnly, Checker: checker},
+		},
+		{
+			name: "grader without hash",
+			evaluation: Evaluation{Type: EvaluationExact, Graders: map[string][]SourceRef{
+				"cpp17": {{Language: "cpp17", FileName: "grader.cpp"}},
+			}},
+			wantErr: errorIncompleteSourceRef(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			err = task.SetEvaluation(tt.evaluation)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if task.GetEvaluation().Type != tt.evaluation.Type {
+					t.Errorf("got type %q, want %q", task.GetEvaluation().Type, tt.evaluation.Type)
+				}
+				if task.GetEvaluation().Graders == nil {
+					t.Errorf("got nil graders, want an empty map")
+				}
+				return
+			}
+			var domainErr *DomainError
+			if !errors.As(err, &domainErr) || err.Error() != tt.wantErr.Error() {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if task.GetEvaluation().Type != DefaultEvaluation().Type {
+				t.Errorf("rejected evaluation was stored")
+			}
+		})
+	}
+}
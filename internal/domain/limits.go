@@ -0,0 +1,110 @@
+package domain
+
+const (
+	maxLimitMultiplier = 10.0
+	maxWallTimeSecs    = 120.0
+)
+
+// LimitOverride scales the task's resource limits for submissions
+// in a particular programming language, e.g. python.
+type LimitOverride struct {
+	CpuMultiplier    float64 // 0 means 1
+	MemoryMultiplier float64 // 0 means 1
+}
+
+type ResourceLimits struct {
+	CpuTimeSecs  float64
+	WallTimeSecs float64
+	MemoryMBytes int
+	StackMBytes  int
+}
+
+func (t *Task) GetWallTimeLimitSecs() float64 {
+	if t.wallTimeLimitSecs == 0 {
+		return 2 * t.cpuTimeLimitSecs
+	}
+	return t.wallTimeLimitSecs
+}
+
+// GetConfiguredWallTimeLimitSecs returns the explicitly set wall time
+// limit, or 0 when the default applies.
+func (t *Task) GetConfiguredWallTimeLimitSecs() float64 {
+	return t.wallTimeLimitSecs
+}
+
+// SetWallTimeLimitSecs sets the wall time limit. Zero resets it to
+// the default of twice the cpu time limit.
+func (t *Task) SetWallTimeLimitSecs(wallTimeLimit float64) error {
+	if wallTimeLimit < 0 || wallTimeLimit > maxWallTimeSecs {
+		return errorWallTimeLimitOutOfRange()
+	}
+	t.wallTimeLimitSecs = wallTimeLimit
+	return nil
+}
+
+func (t *Task) GetStackLimitMBytes() int {
+	if t.stackLimitMBytes == 0 {
+		return t.memoryLimitMBytes
+	}
+	return t.stackLimitMBytes
+}
+
+// GetConfiguredStackLimitMBytes returns the explicitly set stack
+// limit, or 0 when the default applies.
+func (t *Task) GetConfiguredStackLimitMBytes() int {
+	return t.stackLimitMBytes
+}
+
+// SetStackLimitMBytes sets the stack limit. Zero resets it to
+// the default of the memory limit.
+func (t *Task) SetStackLimitMBytes(stackLimit int) error {
+	if stackLimit < 0 {
+		return errorStackLimitMustNotBeNegative()
+	}
+	t.stackLimitMBytes = stackLimit
+	return nil
+}
+
+func (t *Task) GetLimitOverrides() map[string]LimitOverride {
+	return t.limitOverrides
+}
+
+func (t *Task) SetLimitOverride(language string, override LimitOverride) error {
+	for _, multiplier := range []float64{override.CpuMultiplier, override.MemoryMultiplier} {
+		if multiplier < 0 || multiplier > maxLimitMultiplier {
+			return errorLimitMultiplierOutOfRange()
+		}
+	}
+	t.limitOverrides[language] = override
+	return nil
+}
+
+// GetLimits returns the resource limits that apply to submissions in
+// the given programming language. Output-only tasks have no time limits.
+func (t *Task) GetLimits(language string) ResourceLimits {
+	limits := ResourceLimits{
+		CpuTimeSecs:  t.cpuTimeLimitSecs,
+		WallTimeSecs: t.GetWallTimeLimitSecs(),
+		MemoryMBytes: t.memoryLimitMBytes,
+		StackMBytes:  t.GetStackLimitMBytes(),
+	}
+
+	if t.IsOutputOnly() {
+		limits.CpuTimeSecs = 0
+		limits.WallTimeSecs = 0
+	}
+
+	override, ok := t.limitOverrides[language]
+	if !ok {
+		return limits
+	}
+	if override.CpuMultiplier != 0 {
+		limits.CpuTimeSecs *= override.CpuMultiplier
+		limits.WallTimeSecs *= override.CpuMultiplier
+	}
+	if override.MemoryMultiplier != 0 {
+		limits.MemoryMBytes = int(float64(limits.MemoryMBytes) * override.MemoryMultiplier)
+		limits.StackMBytes = int(float64(limits.StackMBytes) * override.MemoryMultiplier)
+	}
+	return limits
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type Olympiad struct {
+	id string
+
+	names       map[string]string // map[language]name
+	year        int
+	stage       string
+	institution string
+}
+
+func NewOlympiad(id string, year int) (*Olympiad, error) {
+	if id == "" {
+		return nil, errorOlympiadIdIsRequired()
+	}
+
+	olympiad := &Olympiad{
+		id:          id,
+		names:       map[string]string{},
+		year:        0,
+		stage:       "",
+		institution: "",
+	}
+
+	err := olympiad.SetYear(year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set olympiad year: %w", err)
+	}
+
+	return olympiad, nil
+}
+
+func (o *Olympiad) GetId() string {
+	return o.id
+}
+
+func (o *Olympiad) GetNames() map[string]string {
+	return o.names
+}
+
+// GetName returns the name in the given language falling back
+// to latvian, english and then any other available language.
+func (o *Olympiad) GetName(language string) string {
+	for _, lang := range []string{language, "lv", "en"} {
+		if name, ok := o.names[lang]; ok {
+			return name
+		}
+	}
+	for _, name := range o.names {
+		return name
+	}
+	return ""
+}
+
+func (o *Olympiad) SetName(language string, name string) {
+	o.names[language] = name
+}
+
+func (o *Olympiad) GetYear() int {
+	return o.year
+}
+
+func (o *Olympiad) SetYear(year int) error {
+	if year <= 0 {
+		return errorOlympiadYearMustBePositive()
+	}
+	o.year = year
+	return nil
+}
+
+func (o *Olympiad) GetStage() string {
+	return o.stage
+}
+
+func (o *Olympiad) SetStage(stage string) {
+	o.stage = stage
+}
+
+func (o *Olympiad) GetInstitution() string {
+	return o.institution
+}
+
+func (o *Olympiad) SetInstitution(institution string) {
+	o.institution = institution
+}
+
+var olympiadNameRegexp = regexp.MustCompile(
+	`^([a-z]+)[\s_-]*(\d{4})((?:[\s_-]+[a-z0-9]+)*)$`)
+
+// ParseOlympiadName maps free-form olympiad names such as "LIO 2023",
+// "LIO2023" and "lio-2023" onto a catalogue id ("lio-2023") and the
+// year of the olympiad. Words after the year name the stage, e.g.
+// "LIO 2023 novada posms" is "lio-2023-novada-posms" of stage
+// "novada posms", so that the stages of a year get distinct ids.
+func ParseOlympiadName(name string) (id string, year int, stage string, ok bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	match := olympiadNameRegexp.FindStringSubmatch(name)
+	if match == nil {
+		return "", 0, "", false
+	}
+	fmt.Sscanf(match[2], "%d", &year)
+	stageWords := strings.FieldsFunc(match[3], func(r rune) bool {
+		return r == '-' || r == '_' || unicode.IsSpace(r)
+	})
+	id = strings.Join(append([]string{match[1], match[2]}, stageWords...), "-")
+	return id, year, strings.Join(stageWords, " "), true
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "testing"
+
+func TestParseOlympiadName(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantId    string
+		wantYear  int
+		wantStage string
+		wantOk    bool
+	}{
+		{name: "LIO 2023", wantId: "lio-2023", wantYear: 2023, wantOk: true},
+		{name: "LIO2023", wantId: "lio-2023", wantYear: 2023, wantOk: true},
+		{name: " lio-2023 ", wantId: "lio-2023", wantYear: 2023, wantOk: true},
+		{name: "LIO 2023 novada posms", wantId: "lio-2023-novada-posms", wantYear: 2023,
+			wantStage: "novada posms", wantOk: true},
+		{name: "LIO_2023_valsts", wantId: "lio-2023-valsts", wantYear: 2023,
+			wantStage: "valsts", wantOk: true},
+		{name: "BOI 2019 day 2", wantId: "boi-2019-day-2", wantYear: 2019,
+			wantStage: "day 2", wantOk: true},
+		{name: "LIO 2023 3. kārta", wantOk: false},
+		{name: "Latvijas olimpiāde", wantOk: false},
+		{name: "2023", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, year, stage, ok := ParseOlympiadName(tt.name)
+			if ok != tt.wantOk || id != tt.wantId || year != tt.wantYear || stage != tt.wantStage {
+				t.Errorf("got (%q, %d, %q, %v), want (%q, %d, %q, %v)", id, year, stage, ok,
+					tt.wantId, tt.wantYear, tt.wantStage, tt.wantOk)
+			}
+		})
+	}
+}
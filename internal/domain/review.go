@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type ReviewStatus string
+
+const (
+	ReviewOpen             ReviewStatus = "open"
+	ReviewApproved         ReviewStatus = "approved"
+	ReviewChangesRequested ReviewStatus = "changes_requested"
+)
+
+// StatementSections are the sections of a markdown statement
+// that review comments may be anchored to.
+var StatementSections = []string{"story", "input", "output", "notes", "scoring"}
+
+// ReviewAnchor is the part of the task a comment refers to: a statement
+// section, a test or, if empty, the task as a whole.
+type ReviewAnchor struct {
+	Language string // statement language, required with Section
+	Section  string // one of StatementSections
+	TestId   *int64
+}
+
+type ReviewComment struct {
+	Author    string
+	Body      string
+	Anchor    ReviewAnchor
+	CreatedAt time.Time
+}
+
+// Review is a reviewer's check of one task revision submitted by its author.
+type Review struct {
+	id          string
+	taskId      string
+	revision    int
+	submittedBy string
+	submittedAt time.Time
+
+	status    ReviewStatus
+	comments  []ReviewComment
+	decidedBy string
+	decidedAt *time.Time
+}
+
+func NewReview(id string, taskId string, revision int,
+	submittedBy string, submittedAt time.Time) *Review {
+	return &Review{
+		id:          id,
+		taskId:      taskId,
+		revision:    revision,
+		submittedBy: submittedBy,
+		submittedAt: submittedAt,
+		status:      ReviewOpen,
+		comments:    []ReviewComment{},
+	}
+}
+
+func (r *Review) GetId() string {
+	return r.id
+}
+
+func (r *Review) GetTaskId() string {
+	return r.taskId
+}
+
+// GetRevision returns the task revision under review.
+func (r *Review) GetRevision() int {
+	return r.revision
+}
+
+func (r *Review) GetSubmittedBy() string {
+	return r.submittedBy
+}
+
+func (r *Review) GetSubmittedAt() time.Time {
+	return r.submittedAt
+}
+
+func (r *Review) GetStatus() ReviewStatus {
+	return r.status
+}
+
+func (r *Review) GetComments() []ReviewComment {
+	return r.comments
+}
+
+func (r *Review) GetDecidedBy() string {
+	return r.decidedBy
+}
+
+func (r *Review) GetDecidedAt() *time.Time {
+	return r.decidedAt
+}
+
+// SetDecision sets the decision without checks,
+// e.g. when loading a stored review.
+func (r *Review) SetDecision(status ReviewStatus, decidedBy string, decidedAt *time.Time) {
+	r.status = status
+	r.decidedBy = decidedBy
+	r.decidedAt = decidedAt
+}
+
+// SetComments sets the comments without checking their anchors,
+// e.g. when loading a stored review.
+func (r *Review) SetComments(comments []ReviewComment) {
+	r.comments = comments
+}
+
+// AddComment adds a comment to an open review after checking that its
+// anchor refers to an existing statement section or test of the task.
+func (r *Review) AddComment(comment ReviewComment, task *Task) error {
+	if r.status != ReviewOpen {
+		return errorReviewIsClosed()
+	}
+	if strings.TrimSpace(comment.Body) == "" {
+		return errorReviewCommentIsEmpty()
+	}
+
+	anchor := comment.Anchor
+	if anchor.Section != "" || anchor.Language != "" {
+		if _, ok := task.GetMarkdownStatements()[anchor.Language]; !ok {
+			return errorInvalidReviewAnchor()
+		}
+		known := false
+		for _, section := range StatementSections {
+			if anchor.Section == section {
+				known = true
+			}
+		}
+		if !known {
+			return errorInvalidReviewAnchor()
+		}
+	}
+	if anchor.TestId != nil {
+		known := false
+		for _, test := range task.GetTests() {
+			if test.TestId == *anchor.TestId {
+				known = true
+			}
+		}
+		if !known {
+			return errorInvalidReviewAnchor()
+		}
+	}
+
+	r.comments = append(r.comments, comment)
+	return nil
+}
+
+// Decide ends an open review with approval or a request for changes.
+// Authors may not review their own submissions.
+func (r *Review) Decide(status ReviewStatus, reviewer string, at time.Time) error {
+	if r.status != ReviewOpen {
+		return errorReviewIsClosed()
+	}
+	if status != ReviewApproved && status != ReviewChangesRequested {
+		return errorUnknownReviewDecision(string(status))
+	}
+	if reviewer == r.submittedBy {
+		return errorCannotReviewOwnSubmission()
+	}
+
+	r.status = status
+	r.decidedBy = reviewer
+	r.decidedAt = &at
+	return nil
+}
+
+// IsApprovalOf reports whether the review approves the task revision.
+func (r *Review) IsApprovalOf(taskId string, revision int) bool {
+	return r.status == ReviewApproved && r.taskId == taskId && r.revision == revision
+}
@@ -0,0 +1,35 @@
+package domain
+
+type ScoringPolicy string
+
+const (
+	// ScoringMin awards the group's points only if every test passes.
+	ScoringMin ScoringPolicy = "min"
+	// ScoringSum splits the group's points evenly among its tests.
+	ScoringSum ScoringPolicy = "sum"
+	// ScoringSumOfPercentages splits the group's points evenly among its
+	// tests and scales each share by the percentage reported by the checker.
+	ScoringSumOfPercentages ScoringPolicy = "sum_of_percentages"
+)
+
+var scoringPolicies = []ScoringPolicy{ScoringMin, ScoringSum, ScoringSumOfPercentages}
+
+// GetScoring returns the group's scoring policy, defaulting to ScoringMin.
+func (g TestGroup) GetScoring() ScoringPolicy {
+	if g.Scoring == "" {
+		return ScoringMin
+	}
+	return g.Scoring
+}
+
+func validateScoringPolicy(policy ScoringPolicy) error {
+	if policy == "" {
+		return nil
+	}
+	for _, scoringPolicy := range scoringPolicies {
+		if policy == scoringPolicy {
+			return nil
+		}
+	}
+	return errorUnknownScoringPolicy(string(policy))
+}
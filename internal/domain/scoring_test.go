@@ -0,0 +1,25 @@
+package domain
+
+import "testing"
+
+func TestValidateScoringPolicy(t *testing.T) {
+	tests := []struct {
+		policy  ScoringPolicy
+		wantErr bool
+	}{
+		{policy: "", wantErr: false},
+		{policy: ScoringMin, wantErr: false},
+		{policy: ScoringSum, wantErr: false},
+		{policy: ScoringSumOfPercentages, wantErr: false},
+		{policy: "max", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			err := validateScoringPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
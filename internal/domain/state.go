@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+type TaskState string
+
+const (
+	TaskStateDraft     TaskState = "draft"
+	TaskStateReview    TaskState = "review"
+	TaskStatePublished TaskState = "published"
+	TaskStateArchived  TaskState = "archived"
+)
+
+// taskStateTransitions lists the states a task may move to from each state.
+var taskStateTransitions = map[TaskState][]TaskState{
+	TaskStateDraft:     {TaskStateReview},
+	TaskStateReview:    {TaskStateDraft, TaskStatePublished},
+	TaskStatePublished: {TaskStateArchived},
+	TaskStateArchived:  {TaskStateDraft},
+}
+
+func (t *Task) GetState() TaskState {
+	return t.state
+}
+
+// SetState sets the state without checking transitions,
+// e.g. when loading a stored task.
+func (t *Task) SetState(state TaskState) error {
+	if _, ok := taskStateTransitions[state]; !ok {
+		return errorUnknownTaskState(string(state))
+	}
+	t.state = state
+	return nil
+}
+
+// TransitionTo moves the task to another lifecycle state
+// if the transition is allowed.
+func (t *Task) TransitionTo(state TaskState) error {
+	if _, ok := taskStateTransitions[state]; !ok {
+		return errorUnknownTaskState(string(state))
+	}
+	for _, allowed := range taskStateTransitions[t.state] {
+		if allowed == state {
+			t.state = state
+			return nil
+		}
+	}
+	return errorInvalidStateTransition(string(t.state), string(state))
+}
+
+// GetPublishAt returns the time from which a published task is publicly
+// visible, or nil if it is visible as soon as it is published.
+func (t *Task) GetPublishAt() *time.Time {
+	return t.publishAt
+}
+
+func (t *Task) SetPublishAt(publishAt *time.Time) {
+	t.publishAt = publishAt
+}
+
+// IsPublic reports whether anonymous users may see the task at the given time.
+func (t *Task) IsPublic(now time.Time) bool {
+	if t.state != TaskStatePublished || t.IsDeleted() {
+		return false
+	}
+	return t.publishAt == nil || !now.Before(*t.publishAt)
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransitionTo(t *testing.T) {
+	states := []TaskState{TaskStateDraft, TaskStateReview, TaskStatePublished, TaskStateArchived}
+	allowed := map[TaskState][]TaskState{
+		TaskStateDraft:     {TaskStateReview},
+		TaskStateReview:    {TaskStateDraft, TaskStatePublished},
+		TaskStatePublished: {TaskStateArchived},
+		TaskStateArchived:  {TaskStateDraft},
+	}
+
+	for _, from := range states {
+		for _, to := range append(states, "hidden") {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				task := newStateTestTask(t)
+				err := task.SetState(from)
+				if err != nil {
+					t.Fatalf("failed to set state: %v", err)
+				}
+
+				want := false
+				for _, state := range allowed[from] {
+					want = want || state == to
+				}
+
+				err = task.TransitionTo(to)
+				if want && err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !want && err == nil {
+					t.Fatalf("transition was allowed")
+				}
+				wantState := from
+				if want {
+					wantState = to
+				}
+				if task.GetState() != wantState {
+					t.Errorf("got state %q, want %q", task.GetState(), wantState)
+				}
+			})
+		}
+	}
+}
+
+func TestSetStateUnknown(t *testing.T) {
+	task := newStateTestTask(t)
+	err := task.SetState(TaskStatePublished)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = task.SetState("hidden")
+	if err == nil || err.Error() != errorUnknownTaskState("hidden").Error() {
+		t.Fatalf("got error %v, want unknown state", err)
+	}
+	if task.GetState() != TaskStatePublished {
+		t.Errorf("got state %q, want %q", task.GetState(), TaskStatePublished)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		state     TaskState
+		publishAt *time.Time
+		want      bool
+	}{
+		{name: "published", state: TaskStatePublished, want: true},
+		{name: "publish time passed", state: TaskStatePublished, publishAt: &before, want: true},
+		{name: "publish time is now", state: TaskStatePublished, publishAt: &now, want: true},
+		{name: "scheduled", state: TaskStatePublished, publishAt: &after, want: false},
+		{name: "draft", state: TaskStateDraft, want: false},
+		{name: "archived", state: TaskStateArchived, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newStateTestTask(t)
+			err := task.SetState(tt.state)
+			if err != nil {
+				t.Fatalf("failed to set state: %v", err)
+			}
+			task.SetPublishAt(tt.publishAt)
+			if got := task.IsPublic(now); got != tt.want {
+				t.Errorf("got public %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newStateTestTask(t *testing.T) *Task {
+	t.Helper()
+	task, err := NewTask("kvadrati", "Kvadrāti")
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	return task
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// TaskStatistics are submission aggregates of a task reported by the judge.
+type TaskStatistics struct {
+	taskId string
+
+	attempts     int     // users who submitted a solution
+	solves       int     // users who got the full score
+	avgBestScore float64 // average of the users' best scores, in percent
+	updatedAt    time.Time
+}
+
+func NewTaskStatistics(taskId string, attempts int, solves int,
+	avgBestScore float64, updatedAt time.Time) (*TaskStatistics, error) {
+	if attempts < 0 || solves < 0 {
+		return nil, errorStatisticsCountIsNegative()
+	}
+	if solves > attempts {
+		return nil, errorSolvesExceedAttempts()
+	}
+	if avgBestScore < 0 || avgBestScore > 100 {
+		return nil, errorAverageScoreOutOfRange()
+	}
+
+	return &TaskStatistics{
+		taskId:       taskId,
+		attempts:     attempts,
+		solves:       solves,
+		avgBestScore: avgBestScore,
+		updatedAt:    updatedAt,
+	}, nil
+}
+
+func (s *TaskStatistics) GetTaskId() string {
+	return s.taskId
+}
+
+func (s *TaskStatistics) GetAttempts() int {
+	return s.attempts
+}
+
+func (s *TaskStatistics) GetSolves() int {
+	return s.solves
+}
+
+func (s *TaskStatistics) GetAvgBestScore() float64 {
+	return s.avgBestScore
+}
+
+func (s *TaskStatistics) GetUpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+// GetSolveRate returns the share of attempting users that solved the task.
+func (s *TaskStatistics) GetSolveRate() float64 {
+	if s.attempts == 0 {
+		return 0
+	}
+	return float64(s.solves) / float64(s.attempts)
+}
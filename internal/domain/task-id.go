@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskID identifies a task in URLs and submissions, e.g. "kvadrputekl".
+// It consists of lowercase ASCII letters and digits, optionally split
+// into words by single hyphens.
+type TaskID string
+
+const (
+	MinTaskIDLength = 2
+	MaxTaskIDLength = 64
+)
+
+func NewTaskID(id string) (TaskID, error) {
+	if len(id) < MinTaskIDLength || len(id) > MaxTaskIDLength {
+		return "", errorTaskIdLength(MinTaskIDLength, MaxTaskIDLength)
+	}
+	for _, r := range id {
+		if !isTaskIDChar(r) && r != '-' {
+			return "", errorTaskIdInvalidCharacter(r)
+		}
+	}
+	if strings.HasPrefix(id, "-") || strings.HasSuffix(id, "-") || strings.Contains(id, "--") {
+		return "", errorTaskIdMisplacedHyphen()
+	}
+	return TaskID(id), nil
+}
+
+func (id TaskID) String() string {
+	return string(id)
+}
+
+// WithSuffix returns the id with "-n" appended, shortening the id
+// so that the result still fits in MaxTaskIDLength.
+func (id TaskID) WithSuffix(n int) TaskID {
+	suffix := fmt.Sprintf("-%d", n)
+	base := string(id)
+	if len(base)+len(suffix) > MaxTaskIDLength {
+		base = strings.TrimRight(base[:MaxTaskIDLength-len(suffix)], "-")
+	}
+	return TaskID(base + suffix)
+}
+
+// latvianTransliteration maps Latvian letters with diacritics
+// to their ASCII base letters.
+var latvianTransliteration = map[rune]rune{
+	'ā': 'a', 'č': 'c', 'ē': 'e', 'ģ': 'g', 'ī': 'i', 'ķ': 'k',
+	'ļ': 'l', 'ņ': 'n', 'ō': 'o', 'ŗ': 'r', 'š': 's', 'ū': 'u', 'ž': 'z',
+}
+
+// TaskIDFromName derives a task id from a task name by transliterating
+// Latvian diacritics and joining the words with hyphens, e.g.
+// "Kvadrātu pūtēkļi" becomes "kvadratu-putekli".
+func TaskIDFromName(name string) (TaskID, error) {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if ascii, ok := latvianTransliteration[r]; ok {
+			r = ascii
+		}
+		if !isTaskIDChar(r) {
+			hyphen = b.Len() > 0
+			continue
+		}
+		if hyphen {
+			b.WriteRune('-')
+			hyphen = false
+		}
+		b.WriteRune(r)
+	}
+
+	slug := b.String()
+	if len(slug) > MaxTaskIDLength {
+		slug = strings.TrimRight(slug[:MaxTaskIDLength], "-")
+	}
+	return NewTaskID(slug)
+}
+
+func isTaskIDChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTaskID(t *testing.T) {
+	tests := []struct {
+		id      string
+		wantErr bool
+	}{
+		{id: "kvadrputekl", wantErr: false},
+		{id: "kvadratu-putekli-2", wantErr: false},
+		{id: "ab", wantErr: false},
+		{id: strings.Repeat("a", MaxTaskIDLength), wantErr: false},
+		{id: "a", wantErr: true},
+		{id: strings.Repeat("a", MaxTaskIDLength+1), wantErr: true},
+		{id: "Kvadrati", wantErr: true},
+		{id: "kvadrāti", wantErr: true},
+		{id: "kvadrati_2", wantErr: true},
+		{id: "-kvadrati", wantErr: true},
+		{id: "kvadrati-", wantErr: true},
+		{id: "kvadrati--2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			id, err := NewTaskID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && id.String() != tt.id {
+				t.Errorf("got id %q, want %q", id, tt.id)
+			}
+		})
+	}
+}
+
+func TestTaskIDFromName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    TaskID
+		wantErr bool
+	}{
+		{name: "Kvadrātu pūtēkļi", want: "kvadratu-putekli"},
+		{name: "  Ģēometrija: ŠŽ (2024)!  ", want: "geometrija-sz-2024"},
+		{name: "A + B", want: "a-b"},
+		{name: strings.Repeat("ab ", 40), want: TaskID(strings.TrimRight(strings.Repeat("ab-", 22)[:MaxTaskIDLength], "-"))},
+		{name: "?", wantErr: true},
+		{name: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := TaskIDFromName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if id != tt.want {
+				t.Errorf("got id %q, want %q", id, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskIDWithSuffix(t *testing.T) {
+	long := TaskID(strings.Repeat("a", MaxTaskIDLength-2) + "-b")
+
+	tests := []struct {
+		id   TaskID
+		n    int
+		want TaskID
+	}{
+		{id: "kvadrati", n: 2, want: "kvadrati-2"},
+		{id: TaskID(strings.Repeat("a", MaxTaskIDLength)), n: 10,
+			want: TaskID(strings.Repeat("a", MaxTaskIDLength-3) + "-10")},
+		{id: long, n: 3, want: TaskID(strings.Repeat("a", MaxTaskIDLength-2) + "-3")},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			got := tt.id.WithSuffix(tt.n)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if _, err := NewTaskID(string(got)); err != nil {
+				t.Errorf("suffixed id is invalid: %v", err)
+			}
+		})
+	}
+}
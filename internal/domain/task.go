@@ -1,17 +1,32 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"time"
+)
 
 type Task struct {
-	id string
+	id       TaskID
+	aliasIds []string // former ids that still resolve to the task
+	revision int      // incremented on every stored change
+
+	state     TaskState
+	publishAt *time.Time // published tasks are hidden until then
+	ownerIds  []string   // users allowed to edit the task
+	deletedAt *time.Time // deleted tasks are hidden until restored
 
 	taskFullName      string
 	memoryLimitMBytes int
 	cpuTimeLimitSecs  float64
-	difficulty        int // [1;5]
+	wallTimeLimitSecs float64                  // 0 means twice the cpu time limit
+	stackLimitMBytes  int                      // 0 means the memory limit
+	limitOverrides    map[string]LimitOverride // map[language]override
+	difficulty        int                      // [1;5]
 	originOlympiad    string
+	originOlympiadId  string
 	problemTags       []string
-	pdfStatements     []pdfSha256Ref
+	pdfStatements     []PdfSha256Ref
 	mdStatements      map[string]*MarkdownStatement // map[language]statement
 	ImgUuidToObjKey   map[string]string
 	examples          []Example
@@ -21,11 +36,12 @@ type Task struct {
 	originNotes map[string]string
 
 	visInpSubtasks []int
-	visInpStInputs map[int][]string
+	visInpStInputs map[int][]VisibleInput
 
 	tests      []TestSha256Ref
 	testGroups []TestGroup
 	subtasks   []Subtask
+	evaluation Evaluation
 }
 
 type TestSha256Ref struct {
@@ -36,19 +52,27 @@ type TestSha256Ref struct {
 
 type TestGroup struct {
 	GroupId    int
+	Points     int
+	Public     bool
 	TestIds    []int
 	SubtaskIds []int
+	Scoring    ScoringPolicy // empty means ScoringMin
 }
 
 type Subtask struct {
-	SubtaskId int
-	TestIds   []int
+	SubtaskId    int
+	TestIds      []int
+	Descriptions map[string]string // map[language]markdown description
 }
 
 type Example struct {
 	Input  string
 	Output string
 	MdNote *string
+
+	SubtaskId *int   // subtask the example belongs to, if any
+	TestId    *int64 // test whose input and answer the example repeats, if any
+	Order     int    // examples are presented in ascending order
 }
 
 type MarkdownStatement struct {
@@ -61,7 +85,31 @@ type MarkdownStatement struct {
 
 type VisInpStInputs struct {
 	Subtask int
-	Inputs  []string
+	Inputs  []VisibleInput
+}
+
+// VisibleInput is an input file shown to contestants. Its content is
+// either stored inline or referenced by the sha256 of a test file blob.
+type VisibleInput struct {
+	Content   *string
+	Sha256    string
+	SizeBytes int
+}
+
+func NewInlineVisibleInput(content string) VisibleInput {
+	return VisibleInput{Content: &content, SizeBytes: len(content)}
+}
+
+func (v VisibleInput) IsInline() bool {
+	return v.Content != nil
+}
+
+func (st VisInpStInputs) TotalSizeBytes() int {
+	total := 0
+	for _, input := range st.Inputs {
+		total += input.SizeBytes
+	}
+	return total
 }
 
 func (t *Task) GetVisInpStInputs() []VisInpStInputs {
@@ -75,10 +123,19 @@ func (t *Task) GetVisInpStInputs() []VisInpStInputs {
 	return res
 }
 
-func (t *Task) AddVisibleInputSubtask(subtask int, inputs []string) {
+// GetVisibleInputs returns the visible inputs of the subtask and
+// whether the subtask has visible inputs at all.
+func (t *Task) GetVisibleInputs(subtask int) ([]VisibleInput, bool) {
+	if !t.IsVisibleInputSubtask(subtask) {
+		return nil, false
+	}
+	return t.visInpStInputs[subtask], true
+}
+
+func (t *Task) AddVisibleInputSubtask(subtask int, inputs []VisibleInput) {
 	t.visInpSubtasks = append(t.visInpSubtasks, subtask)
 	if t.visInpStInputs == nil {
-		t.visInpStInputs = make(map[int][]string)
+		t.visInpStInputs = make(map[int][]VisibleInput)
 	}
 	t.visInpStInputs[subtask] = inputs
 }
@@ -91,8 +148,54 @@ func (t *Task) SetImgUuidToObjKey(imgUuidToObjKey map[string]string) {
 	t.ImgUuidToObjKey = imgUuidToObjKey
 }
 
+// GetExamples returns the examples sorted by their ordering key.
 func (t *Task) GetExamples() []Example {
-	return t.examples
+	res := append([]Example{}, t.examples...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Order < res[j].Order
+	})
+	return res
+}
+
+type SubtaskExamples struct {
+	SubtaskId *int // nil for examples not tied to a subtask
+	Examples  []Example
+}
+
+// GetExamplesBySubtask groups the ordered examples per subtask. Examples
+// without a subtask come first, followed by subtasks in ascending order.
+func (t *Task) GetExamplesBySubtask() []SubtaskExamples {
+	res := []SubtaskExamples{}
+	for _, example := range t.GetExamples() {
+		found := false
+		for i := range res {
+			if equalIntPtr(res[i].SubtaskId, example.SubtaskId) {
+				res[i].Examples = append(res[i].Examples, example)
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, SubtaskExamples{
+				SubtaskId: example.SubtaskId,
+				Examples:  []Example{example},
+			})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].SubtaskId == nil || res[j].SubtaskId == nil {
+			return res[i].SubtaskId == nil && res[j].SubtaskId != nil
+		}
+		return *res[i].SubtaskId < *res[j].SubtaskId
+	})
+	return res
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
 }
 
 func (t *Task) AddExample(example Example) {
@@ -108,12 +211,25 @@ func (t *Task) SetOriginNotes(notes map[string]string) {
 }
 
 func (t *Task) GetDefaultMarkdownStatement() *MarkdownStatement {
-	for _, lang := range []string{"lv", "en", ""} {
+	_, statement := t.GetPreferredMarkdownStatement(nil)
+	return statement
+}
+
+// GetPreferredMarkdownStatement returns the markdown statement and its
+// language in the first available language of languages, falling back
+// to latvian, english and the statement without a language.
+func (t *Task) GetPreferredMarkdownStatement(languages []string) (string, *MarkdownStatement) {
+	candidates := append(append([]string{}, languages...), "lv", "en", "")
+	for _, lang := range candidates {
 		if _, ok := t.mdStatements[lang]; ok {
-			return t.mdStatements[lang]
+			return lang, t.mdStatements[lang]
 		}
 	}
-	return nil
+	return "", nil
+}
+
+func (t *Task) GetMarkdownStatements() map[string]*MarkdownStatement {
+	return t.mdStatements
 }
 
 func (t *Task) AddMarkdownStatement(language string, statement MarkdownStatement) {
@@ -129,7 +245,32 @@ func (t *Task) SetIllustrationImgObjKey(key string) {
 }
 
 func (t *Task) GetId() string {
-	return t.id
+	return t.id.String()
+}
+
+func (t *Task) GetRevision() int {
+	return t.revision
+}
+
+func (t *Task) SetRevision(revision int) {
+	t.revision = revision
+}
+
+func (t *Task) GetOwnerIds() []string {
+	return t.ownerIds
+}
+
+func (t *Task) SetOwnerIds(ownerIds []string) {
+	t.ownerIds = ownerIds
+}
+
+func (t *Task) IsOwnedBy(userId string) bool {
+	for _, ownerId := range t.ownerIds {
+		if ownerId == userId {
+			return true
+		}
+	}
+	return false
 }
 
 func (t *Task) GetTaskFullName() string {
@@ -152,32 +293,86 @@ func (t *Task) GetOriginOlympiad() string {
 	return t.originOlympiad
 }
 
+func (t *Task) GetOriginOlympiadId() string {
+	return t.originOlympiadId
+}
+
 func (t *Task) GetProblemTags() []string {
 	return t.problemTags
 }
 
 func (t *Task) GetLvOrOtherPdfSha256() string {
-	if len(t.pdfStatements) == 0 {
+	pdf := t.GetPreferredPdfStatement(nil)
+	if pdf == nil {
 		return ""
 	}
-	return t.pdfStatements[0].Sha256
+	return pdf.Sha256
+}
+
+// GetPreferredPdfStatement returns the pdf statement in the first
+// available language of languages, falling back to latvian, english
+// and then the first stored statement.
+func (t *Task) GetPreferredPdfStatement(languages []string) *PdfSha256Ref {
+	if len(t.pdfStatements) == 0 {
+		return nil
+	}
+	candidates := append(append([]string{}, languages...), "lv", "en")
+	for _, lang := range candidates {
+		for i := range t.pdfStatements {
+			if t.pdfStatements[i].Language == lang {
+				return &t.pdfStatements[i]
+			}
+		}
+	}
+	return &t.pdfStatements[0]
 }
 
-type pdfSha256Ref struct {
+func (t *Task) GetPdfStatementSha256s() []PdfSha256Ref {
+	return t.pdfStatements
+}
+
+type PdfSha256Ref struct {
 	Language string
 	Sha256   string
 }
 
+// EmptyTask returns a task without any fields set. It stands for
+// revision 0 of a task, the state before its first revision.
+func EmptyTask(id string) *Task {
+	return &Task{id: TaskID(id)}
+}
+
 func NewTask(id string, fullName string) (*Task, error) {
+	taskId, err := NewTaskID(id)
+	if err != nil {
+		return nil, err
+	}
+	return newTask(taskId, fullName)
+}
+
+// NewStoredTask creates a task with an id it was stored under. The id is
+// taken as it is, since ids stored before the id rules may not follow
+// them; lint reports such ids.
+func NewStoredTask(id string, fullName string) (*Task, error) {
+	return newTask(TaskID(id), fullName)
+}
+
+func newTask(taskId TaskID, fullName string) (*Task, error) {
 	task := &Task{
-		id:                    id,
+		id:                    taskId,
+		aliasIds:              []string{},
+		state:                 TaskStateDraft,
+		ownerIds:              []string{},
 		taskFullName:          "",
 		memoryLimitMBytes:     256,
 		cpuTimeLimitSecs:      1.0,
+		limitOverrides:        map[string]LimitOverride{},
+		evaluation:            DefaultEvaluation(),
 		difficulty:            1,
 		originOlympiad:        "",
+		originOlympiadId:      "",
 		problemTags:           []string{},
-		pdfStatements:         []pdfSha256Ref{},
+		pdfStatements:         []PdfSha256Ref{},
 		mdStatements:          map[string]*MarkdownStatement{},
 		examples:              []Example{},
 		illustrationImgObjKey: "",
@@ -200,11 +395,28 @@ func (t *Task) SetTaskFullName(fullName string) error {
 	return nil
 }
 
-func (t *Task) SetMemoryLimitMBytes(memoryLimit int) {
+func (t *Task) SetMemoryLimitMBytes(memoryLimit int) error {
+	if memoryLimit <= 0 {
+		return errorMemoryLimitMustBePositive()
+	}
 	t.memoryLimitMBytes = memoryLimit
+	return nil
+}
+
+func (t *Task) SetCpuTimeLimitSecs(cpuTimeLimit float64) error {
+	if cpuTimeLimit <= 0 {
+		return errorCpuTimeLimitMustBePositive()
+	}
+	t.cpuTimeLimitSecs = cpuTimeLimit
+	return nil
 }
 
-func (t *Task) SetCpuTimeLimitSecs(cpuTimeLimit float64) {
+// SetStoredLimits sets the difficulty and the memory and cpu time limits
+// without range checks, e.g. when loading a stored task that was written
+// before the checks existed. Lint reports such values.
+func (t *Task) SetStoredLimits(difficulty int, memoryLimit int, cpuTimeLimit float64) {
+	t.difficulty = difficulty
+	t.memoryLimitMBytes = memoryLimit
 	t.cpuTimeLimitSecs = cpuTimeLimit
 }
 
@@ -212,14 +424,52 @@ func (t *Task) SetOriginOlympiad(origin string) {
 	t.originOlympiad = origin
 }
 
+func (t *Task) SetOriginOlympiadId(olympiadId string) {
+	t.originOlympiadId = olympiadId
+}
+
 func (t *Task) AddPdfStatementSha256(language string, sha256 string) {
-	t.pdfStatements = append(t.pdfStatements, pdfSha256Ref{Language: language, Sha256: sha256})
+	t.pdfStatements = append(t.pdfStatements, PdfSha256Ref{Language: language, Sha256: sha256})
+}
+
+func (t *Task) RemovePdfStatementSha256s(language string) {
+	kept := []PdfSha256Ref{}
+	for _, pdf := range t.pdfStatements {
+		if pdf.Language != language {
+			kept = append(kept, pdf)
+		}
+	}
+	t.pdfStatements = kept
 }
 
 func (t *Task) SetProblemTags(tags []string) {
 	t.problemTags = tags
 }
 
+// AddProblemTag adds the tag unless the task already has it.
+func (t *Task) AddProblemTag(tag string) error {
+	if tag == "" {
+		return errorProblemTagIsEmpty()
+	}
+	for _, existing := range t.problemTags {
+		if existing == tag {
+			return nil
+		}
+	}
+	t.problemTags = append(t.problemTags, tag)
+	return nil
+}
+
+func (t *Task) RemoveProblemTag(tag string) {
+	kept := []string{}
+	for _, existing := range t.problemTags {
+		if existing != tag {
+			kept = append(kept, existing)
+		}
+	}
+	t.problemTags = kept
+}
+
 func (t *Task) SetDifficulty(difficulty int) error {
 	if difficulty < 1 || difficulty > 5 {
 		return errorDifficultyMustBeBetweenOneAndFive()
@@ -245,8 +495,15 @@ func (t *Task) GetTests() []TestSha256Ref {
 	return t.tests
 }
 
-func (t *Task) SetTestGroups(groups []TestGroup) {
+func (t *Task) SetTestGroups(groups []TestGroup) error {
+	for _, group := range groups {
+		err := validateScoringPolicy(group.Scoring)
+		if err != nil {
+			return err
+		}
+	}
 	t.testGroups = groups
+	return nil
 }
 
 func (t *Task) GetTestGroups() []TestGroup {
@@ -260,3 +517,26 @@ func (t *Task) SetSubtasks(subtasks []Subtask) {
 func (t *Task) GetSubtasks() []Subtask {
 	return t.subtasks
 }
+
+// GetSubtaskPoints sums the points of the test groups of the subtask.
+func (t *Task) GetSubtaskPoints(subtaskId int) int {
+	points := 0
+	for _, group := range t.testGroups {
+		for _, id := range group.SubtaskIds {
+			if id == subtaskId {
+				points += group.Points
+				break
+			}
+		}
+	}
+	return points
+}
+
+func (t *Task) IsVisibleInputSubtask(subtaskId int) bool {
+	for _, st := range t.visInpSubtasks {
+		if st == subtaskId {
+			return true
+		}
+	}
+	return false
+}
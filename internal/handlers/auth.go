@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// authenticate verifies the bearer token, if any, and puts the caller
+// identity into the request context. Requests without a token proceed
+// anonymously; requests with an invalid token are rejected.
+func (c *Controller) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			respondWithError(w, r, domain.ErrorInvalidToken(), "")
+			return
+		}
+		identity, err := c.verifier.Verify(token)
+		if err != nil {
+			respondWithError(w, r, domain.ErrorInvalidToken(), "")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	})
+}
+
+// requireRole rejects requests whose caller has none of the roles.
+func requireRole(roles ...auth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity := auth.IdentityFromContext(r.Context())
+			if identity == nil {
+				respondWithError(w, r, domain.ErrorAuthenticationRequired(), "")
+				return
+			}
+			for _, role := range roles {
+				if identity.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			respondWithError(w, r, domain.ErrorForbidden(), "")
+		})
+	}
+}
+
+// canPreviewUnpublished reports whether the caller may see tasks that
+// are not publicly visible yet, e.g. drafts.
+func canPreviewUnpublished(r *http.Request) bool {
+	identity := auth.IdentityFromContext(r.Context())
+	return identity.HasRole(auth.RoleAuthor) || identity.HasRole(auth.RoleReviewer)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+)
+
+func TestAuthenticateAndRequireRole(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	token := func(roles ...string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+			"sub":   "user-1",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": roles,
+		}).SignedString(privateKey)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return "Bearer " + signed
+	}
+	c := &Controller{verifier: auth.NewJwtVerifier([]interface{}{publicKey}, "")}
+
+	var seen *auth.Identity
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = auth.IdentityFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name        string
+		header      string
+		roles       []auth.Role // nil for a public route
+		wantStatus  int
+		wantSubject string
+	}{
+		{name: "anonymous on a public route", wantStatus: http.StatusNoContent},
+		{name: "token on a public route", header: token(), wantStatus: http.StatusNoContent,
+			wantSubject: "user-1"},
+		{name: "not a bearer token", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", header: "Bearer not.a.token", wantStatus: http.StatusUnauthorized},
+		{name: "anonymous on a protected route", roles: []auth.Role{auth.RoleAuthor},
+			wantStatus: http.StatusUnauthorized},
+		{name: "missing role", header: token("reviewer"), roles: []auth.Role{auth.RoleAuthor},
+			wantStatus: http.StatusForbidden},
+		{name: "one of the roles", header: token("reviewer"),
+			roles: []auth.Role{auth.RoleAuthor, auth.RoleReviewer}, wantStatus: http.StatusNoContent,
+			wantSubject: "user-1"},
+		{name: "admin", header: token("admin"), roles: []auth.Role{auth.RoleAuthor},
+			wantStatus: http.StatusNoContent, wantSubject: "user-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			var route http.Handler = handler
+			if tt.roles != nil {
+				route = requireRole(tt.roles...)(route)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			c.authenticate(route).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			subject := ""
+			if seen != nil {
+				subject = seen.Subject
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("got subject %q, want %q", subject, tt.wantSubject)
+			}
+		})
+	}
+}
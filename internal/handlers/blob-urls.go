@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+// blobUrlBuilder resolves public bucket object keys and content-addressed
+// blobs to urls served through the CloudFront distribution.
+type blobUrlBuilder struct {
+	publicBucketCloudFrontHost string
+}
+
+func (b blobUrlBuilder) objectUrl(objKey string) string {
+	if b.publicBucketCloudFrontHost == "" || objKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/%s", b.publicBucketCloudFrontHost, objKey)
+}
+
+func (b blobUrlBuilder) pdfStatementUrl(sha256 string) string {
+	if sha256 == "" {
+		return ""
+	}
+	return b.objectUrl(service.PdfStatementObjKey(sha256))
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+type CollectionRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	TaskIds     []string `json:"task_ids"`
+}
+
+func (c *Controller) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var req CollectionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := c.collectionSrv.CreateCollection(auth.IdentityFromContext(r.Context()), service.CollectionParams{
+		Title:       req.Title,
+		Description: req.Description,
+		TaskIds:     req.TaskIds,
+	})
+	if err != nil {
+		respondWithError(w, r, err, "failed to create collection")
+		return
+	}
+
+	respondWithJSON(w, mapDomainCollectionToCollectionResponse(collection), http.StatusCreated)
+}
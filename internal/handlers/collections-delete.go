@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+)
+
+func (c *Controller) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid collection id", http.StatusBadRequest)
+		return
+	}
+
+	err := c.collectionSrv.DeleteCollection(auth.IdentityFromContext(r.Context()), id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to delete collection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type GetCollectionResponse struct {
+	Collection     Collection `json:"collection"`
+	Tasks          []Task     `json:"tasks"`
+	MissingTaskIds []string   `json:"missing_task_ids"`
+}
+
+type Collection struct {
+	CollectionId string   `json:"collection_id"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description,omitempty"`
+	Owner        string   `json:"owner"`
+	TaskIds      []string `json:"task_ids"`
+}
+
+func (c *Controller) GetCollection(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid collection id", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseTaskResponseOptions(r)
+	if err != nil {
+		respondWithJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	view, err := c.collectionSrv.GetCollection(id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to get collection")
+		return
+	}
+
+	tasks := []Task{}
+	for _, task := range view.Tasks {
+		tasks = append(tasks, c.mapDomainTaskToTaskResponse(&task, opts))
+	}
+	respondWithJSON(w, GetCollectionResponse{
+		Collection:     mapDomainCollectionToCollectionResponse(view.Collection),
+		Tasks:          tasks,
+		MissingTaskIds: view.MissingTaskIds,
+	}, http.StatusOK)
+}
+
+func mapDomainCollectionToCollectionResponse(collection *domain.Collection) Collection {
+	taskIds := collection.GetTaskIds()
+	if taskIds == nil {
+		taskIds = []string{}
+	}
+	return Collection{
+		CollectionId: collection.GetId(),
+		Title:        collection.GetTitle(),
+		Description:  collection.GetDescription(),
+		Owner:        collection.GetOwner(),
+		TaskIds:      taskIds,
+	}
+}
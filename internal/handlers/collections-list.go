@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+)
+
+type ListCollectionsResponse struct {
+	Collections []Collection `json:"collections"`
+}
+
+func (c *Controller) ListCollections(w http.ResponseWriter, r *http.Request) {
+	domainCollectionObjs, err := c.collectionSrv.ListCollections()
+	if err != nil {
+		log.Printf("failed to list collections: %v", err)
+		respondWithJSON(w, "failed to list collections", http.StatusInternalServerError)
+		return
+	}
+
+	collections := []Collection{}
+	for _, collection := range domainCollectionObjs {
+		collections = append(collections, mapDomainCollectionToCollectionResponse(&collection))
+	}
+	respondWithJSON(w, ListCollectionsResponse{
+		Collections: collections,
+	}, http.StatusOK)
+}
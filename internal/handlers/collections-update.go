@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+func (c *Controller) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid collection id", http.StatusBadRequest)
+		return
+	}
+
+	var req CollectionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := c.collectionSrv.UpdateCollection(auth.IdentityFromContext(r.Context()), id, service.CollectionParams{
+		Title:       req.Title,
+		Description: req.Description,
+		TaskIds:     req.TaskIds,
+	})
+	if err != nil {
+		respondWithError(w, r, err, "failed to update collection")
+		return
+	}
+
+	respondWithJSON(w, mapDomainCollectionToCollectionResponse(collection), http.StatusOK)
+}
@@ -3,29 +3,111 @@ package handlers
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/rendering"
 	"github.com/programme-lv/tasks-microservice/internal/service"
 )
 
 type Controller struct {
-	taskSrv *service.TaskService
+	taskSrv           *service.TaskService
+	olympiadSrv       *service.OlympiadService
+	collectionSrv     *service.CollectionService
+	visibleInputSrv   *service.VisibleInputService
+	auditSrv          *service.AuditService
+	reviewSrv         *service.ReviewService
+	statisticsSrv     *service.StatisticsService
+	recommendationSrv *service.RecommendationService
 
-	publicBucketCloudFrontHost string
+	verifier *auth.JwtVerifier
+
+	blobUrls              blobUrlBuilder
+	statementRenderer     *rendering.StatementRenderer
+	htmlStatementRenderer *rendering.HtmlStatementRenderer
 }
 
-func NewController(taskSrv *service.TaskService) *Controller {
-	return &Controller{
-		taskSrv:                    taskSrv,
+func NewController(taskSrv *service.TaskService,
+	olympiadSrv *service.OlympiadService,
+	collectionSrv *service.CollectionService,
+	visibleInputSrv *service.VisibleInputService,
+	auditSrv *service.AuditService,
+	reviewSrv *service.ReviewService,
+	statisticsSrv *service.StatisticsService,
+	recommendationSrv *service.RecommendationService,
+	verifier *auth.JwtVerifier) *Controller {
+	blobUrls := blobUrlBuilder{
 		publicBucketCloudFrontHost: "dvhk4hiwp1rmf.cloudfront.net",
 	}
+	statementRenderer := rendering.NewStatementRenderer(blobUrls.objectUrl)
+	return &Controller{
+		taskSrv:               taskSrv,
+		olympiadSrv:           olympiadSrv,
+		collectionSrv:         collectionSrv,
+		visibleInputSrv:       visibleInputSrv,
+		auditSrv:              auditSrv,
+		reviewSrv:             reviewSrv,
+		statisticsSrv:         statisticsSrv,
+		recommendationSrv:     recommendationSrv,
+		verifier:              verifier,
+		blobUrls:              blobUrls,
+		statementRenderer:     statementRenderer,
+		htmlStatementRenderer: rendering.NewHtmlStatementRenderer(statementRenderer),
+	}
 }
 
 func (c *Controller) RegisterRoutes(r chi.Router) {
 	r.Use(middleware.Logger)
+	r.Use(c.authenticate)
 
+	r.With(requireRole(auth.RoleAuthor)).Post("/tasks:batch", c.BatchUpdateTasks)
 	r.Route("/tasks", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
 			r.Get("/", c.ListTasks)
 			r.Get("/{id}", c.GetTask)
+			r.Get("/{id}/statement.pdf", c.GetTaskPdfStatement)
+			r.Get("/{id}/evaluation", c.GetTaskEvaluation)
+			r.Get("/{id}/subtasks/{st}/inputs.zip", c.GetVisibleInputsZip)
+			r.Get("/{id}/inputs.zip", c.GetOutputOnlyInputsZip)
+			r.Get("/{id}/similar", c.ListSimilarTasks)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(requireRole(auth.RoleAuthor, auth.RoleReviewer))
+			r.Delete("/{id}", c.DeleteTask)
+			r.Post("/{id}/restore", c.RestoreTask)
+			r.Put("/{id}/state", c.ChangeTaskState)
+			r.Get("/{id}/diff", c.GetTaskDiff)
+			r.Get("/{id}/reviews", c.ListReviews)
+			r.Post("/{id}/reviews", c.SubmitForReview)
+			r.Get("/{id}/reviews/{reviewId}", c.GetReview)
+			r.Post("/{id}/reviews/{reviewId}/comments", c.AddReviewComment)
+			r.Post("/{id}/reviews/{reviewId}/decision", c.DecideReview)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(requireRole(auth.RoleAdmin))
+			r.Get("/{id}/audit", c.ListTaskAudit)
+			r.Put("/{id}/owners", c.SetTaskOwners)
+		})
+	})
+
+	r.Post("/recommendations", c.RecommendTasks)
+
+	r.Route("/statistics", func(r chi.Router) {
+		r.Use(requireRole(auth.RoleJudge))
+		r.Post("/tasks", c.IngestTaskStatistics)
+	})
+
+	r.Route("/olympiads", func(r chi.Router) {
+		r.Get("/", c.ListOlympiads)
+		r.Get("/{id}/tasks", c.ListOlympiadTasks)
+	})
+
+	r.Route("/collections", func(r chi.Router) {
+		r.Get("/", c.ListCollections)
+		r.Get("/{id}", c.GetCollection)
+		r.Group(func(r chi.Router) {
+			r.Use(requireRole(auth.RoleViewer))
+			r.Post("/", c.CreateCollection)
+			r.Put("/{id}", c.UpdateCollection)
+			r.Delete("/{id}", c.DeleteCollection)
 		})
 	})
 }
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
 )
 
 func respondWithJSON(w http.ResponseWriter, body interface{}, statusCode int) {
@@ -16,3 +20,29 @@ func respondWithJSON(w http.ResponseWriter, body interface{}, statusCode int) {
 	w.WriteHeader(statusCode)
 	w.Write(jsonResponse)
 }
+
+// respondWithError responds with the localized message and status code
+// of a domain error, or with a generic internal server error otherwise.
+func respondWithError(w http.ResponseWriter, r *http.Request, err error, fallbackMsg string) {
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) {
+		log.Printf("%s: %v", fallbackMsg, err)
+		respondWithJSON(w, fallbackMsg, http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, localizedErrorMessage(r, domainErr), domainErr.StatusCode)
+}
+
+// localizedErrorMessage returns the message of a domain error in the
+// language the client prefers, falling back to English.
+func localizedErrorMessage(r *http.Request, domainErr *domain.DomainError) string {
+	msg := domainErr.I18NErrors["en"]
+	for _, lang := range preferredLanguages(r) {
+		if localized, ok := domainErr.I18NErrors[lang]; ok {
+			msg = localized
+			break
+		}
+	}
+	return msg.Error()
+}
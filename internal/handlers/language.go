@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// preferredLanguages returns the languages requested by the client,
+// most preferred first. The "lang" query parameter takes precedence
+// over the Accept-Language header.
+func preferredLanguages(r *http.Request) []string {
+	languages := []string{}
+	if lang := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lang"))); lang != "" {
+		languages = append(languages, lang)
+	}
+
+	type weightedLanguage struct {
+		lang   string
+		weight float64
+	}
+	weighted := []weightedLanguage{}
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := strings.ToLower(strings.TrimSpace(fields[0]))
+		if lang == "" || lang == "*" {
+			continue
+		}
+		if i := strings.Index(lang, "-"); i > 0 {
+			lang = lang[:i]
+		}
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if q, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					weight = parsed
+				}
+			}
+		}
+		if weight <= 0 {
+			// q=0 marks the language as not acceptable
+			continue
+		}
+		weighted = append(weighted, weightedLanguage{lang: lang, weight: weight})
+	}
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].weight > weighted[j].weight
+	})
+	for _, w := range weighted {
+		languages = append(languages, w.lang)
+	}
+
+	return languages
+}
+
+// localize picks the text in the first available preferred language,
+// falling back to latvian, english and then any language.
+func localize(texts map[string]string, languages []string) string {
+	for _, lang := range append(append([]string{}, languages...), "lv", "en") {
+		if text, ok := texts[lang]; ok {
+			return text
+		}
+	}
+	for _, text := range texts {
+		return text
+	}
+	return ""
+}
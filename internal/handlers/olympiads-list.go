@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ListOlympiadsResponse struct {
+	Olympiads []Olympiad `json:"olympiads"`
+}
+
+type Olympiad struct {
+	OlympiadId  string            `json:"olympiad_id"`
+	Names       map[string]string `json:"names"`
+	Year        int               `json:"year"`
+	Stage       string            `json:"stage,omitempty"`
+	Institution string            `json:"institution,omitempty"`
+}
+
+func (c *Controller) ListOlympiads(w http.ResponseWriter, r *http.Request) {
+	domainOlympiadObjs, err := c.olympiadSrv.ListOlympiads()
+	if err != nil {
+		log.Printf("failed to list olympiads: %v", err)
+		respondWithJSON(w, "failed to list olympiads", http.StatusInternalServerError)
+		return
+	}
+
+	olympiads := []Olympiad{}
+	for _, olympiad := range domainOlympiadObjs {
+		olympiads = append(olympiads, mapDomainOlympiadToOlympiadResponse(&olympiad))
+	}
+	respondWithJSON(w, ListOlympiadsResponse{
+		Olympiads: olympiads,
+	}, http.StatusOK)
+}
+
+func mapDomainOlympiadToOlympiadResponse(olympiad *domain.Olympiad) Olympiad {
+	return Olympiad{
+		OlympiadId:  olympiad.GetId(),
+		Names:       olympiad.GetNames(),
+		Year:        olympiad.GetYear(),
+		Stage:       olympiad.GetStage(),
+		Institution: olympiad.GetInstitution(),
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (c *Controller) ListOlympiadTasks(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid olympiad id", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseTaskResponseOptions(r)
+	if err != nil {
+		respondWithJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	domainTaskObjs, err := c.olympiadSrv.ListOlympiadTasks(id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to list olympiad tasks")
+		return
+	}
+
+	tasks := []Task{}
+	for _, task := range domainTaskObjs {
+		tasks = append(tasks, c.mapDomainTaskToTaskResponse(&task, opts))
+	}
+	respondWithJSON(w, ListTasksResponse{
+		Tasks: tasks,
+	}, http.StatusOK)
+}
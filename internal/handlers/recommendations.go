@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type RecommendTasksRequest struct {
+	SolvedTaskIds []string `json:"solved_task_ids"`
+	Limit         int      `json:"limit"`
+}
+
+func (c *Controller) RecommendTasks(w http.ResponseWriter, r *http.Request) {
+	var req RecommendTasksRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.Limit < 0 {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseTaskResponseOptions(r)
+	if err != nil {
+		respondWithJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scored, err := c.recommendationSrv.RecommendTasks(req.SolvedTaskIds, req.Limit)
+	if err != nil {
+		respondWithError(w, r, err, "failed to recommend tasks")
+		return
+	}
+
+	respondWithJSON(w, c.mapScoredTasksToResponse(scored, opts), http.StatusOK)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// ReviewCommentRequest is a comment anchored to a statement section
+// (language and section), a test or, with neither, the whole task.
+type ReviewCommentRequest struct {
+	Body     string `json:"body"`
+	Language string `json:"language"`
+	Section  string `json:"section"`
+	TestId   *int64 `json:"test_id"`
+}
+
+func (c *Controller) AddReviewComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	reviewId := chi.URLParam(r, "reviewId")
+	if id == "" || reviewId == "" {
+		respondWithJSON(w, "invalid task or review id", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewCommentRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := c.reviewSrv.AddComment(auth.IdentityFromContext(r.Context()), id, reviewId,
+		req.Body, domain.ReviewAnchor{
+			Language: req.Language,
+			Section:  req.Section,
+			TestId:   req.TestId,
+		})
+	if err != nil {
+		respondWithError(w, r, err, "failed to add review comment")
+		return
+	}
+
+	respondWithJSON(w, mapDomainReviewToReviewResponse(review), http.StatusCreated)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ReviewDecisionRequest struct {
+	Decision string `json:"decision"` // "approved" or "changes_requested"
+}
+
+func (c *Controller) DecideReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	reviewId := chi.URLParam(r, "reviewId")
+	if id == "" || reviewId == "" {
+		respondWithJSON(w, "invalid task or review id", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewDecisionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := c.reviewSrv.DecideReview(auth.IdentityFromContext(r.Context()), id, reviewId,
+		domain.ReviewStatus(req.Decision))
+	if err != nil {
+		respondWithError(w, r, err, "failed to decide review")
+		return
+	}
+
+	respondWithJSON(w, mapDomainReviewToReviewResponse(review), http.StatusOK)
+}
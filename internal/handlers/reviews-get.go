@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type Review struct {
+	ReviewId    string          `json:"review_id"`
+	TaskId      string          `json:"published_task_id"`
+	Revision    int             `json:"revision"`
+	SubmittedBy string          `json:"submitted_by"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	Status      string          `json:"status"`
+	Comments    []ReviewComment `json:"comments"`
+	DecidedBy   string          `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time      `json:"decided_at,omitempty"`
+}
+
+type ReviewComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Language  string    `json:"language,omitempty"`
+	Section   string    `json:"section,omitempty"`
+	TestId    *int64    `json:"test_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *Controller) GetReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	reviewId := chi.URLParam(r, "reviewId")
+	if id == "" || reviewId == "" {
+		respondWithJSON(w, "invalid task or review id", http.StatusBadRequest)
+		return
+	}
+
+	review, err := c.reviewSrv.GetReview(id, reviewId)
+	if err != nil {
+		respondWithError(w, r, err, "failed to get review")
+		return
+	}
+
+	respondWithJSON(w, mapDomainReviewToReviewResponse(review), http.StatusOK)
+}
+
+func mapDomainReviewToReviewResponse(review *domain.Review) Review {
+	comments := []ReviewComment{}
+	for _, comment := range review.GetComments() {
+		comments = append(comments, ReviewComment{
+			Author:    comment.Author,
+			Body:      comment.Body,
+			Language:  comment.Anchor.Language,
+			Section:   comment.Anchor.Section,
+			TestId:    comment.Anchor.TestId,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	return Review{
+		ReviewId:    review.GetId(),
+		TaskId:      review.GetTaskId(),
+		Revision:    review.GetRevision(),
+		SubmittedBy: review.GetSubmittedBy(),
+		SubmittedAt: review.GetSubmittedAt(),
+		Status:      string(review.GetStatus()),
+		Comments:    comments,
+		DecidedBy:   review.GetDecidedBy(),
+		DecidedAt:   review.GetDecidedAt(),
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ListReviewsResponse struct {
+	Reviews []Review `json:"reviews"`
+}
+
+func (c *Controller) ListReviews(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	domainReviewObjs, err := c.reviewSrv.ListTaskReviews(id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to list reviews")
+		return
+	}
+
+	reviews := []Review{}
+	for _, review := range domainReviewObjs {
+		reviews = append(reviews, mapDomainReviewToReviewResponse(&review))
+	}
+	respondWithJSON(w, ListReviewsResponse{
+		Reviews: reviews,
+	}, http.StatusOK)
+}
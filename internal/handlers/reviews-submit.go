@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+)
+
+// SubmitForReview moves a draft task into review and opens a review.
+func (c *Controller) SubmitForReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	review, err := c.reviewSrv.SubmitForReview(auth.IdentityFromContext(r.Context()), id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to submit task for review")
+		return
+	}
+
+	respondWithJSON(w, mapDomainReviewToReviewResponse(review), http.StatusCreated)
+}
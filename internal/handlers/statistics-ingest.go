@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+type IngestTaskStatisticsRequest struct {
+	Tasks []TaskStatisticsReport `json:"tasks"`
+}
+
+type TaskStatisticsReport struct {
+	TaskId       string  `json:"task_id"`
+	Attempts     int     `json:"attempts"`
+	Solves       int     `json:"solves"`
+	AvgBestScore float64 `json:"avg_best_score"`
+}
+
+type IngestTaskStatisticsResponse struct {
+	Ingested       int      `json:"ingested"`
+	UnknownTaskIds []string `json:"unknown_task_ids"`
+}
+
+func (c *Controller) IngestTaskStatistics(w http.ResponseWriter, r *http.Request) {
+	var req IngestTaskStatisticsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reports := make([]service.TaskStatisticsReport, 0, len(req.Tasks))
+	for _, report := range req.Tasks {
+		reports = append(reports, service.TaskStatisticsReport{
+			TaskId:       report.TaskId,
+			Attempts:     report.Attempts,
+			Solves:       report.Solves,
+			AvgBestScore: report.AvgBestScore,
+		})
+	}
+
+	unknownTaskIds, err := c.statisticsSrv.IngestStatistics(
+		auth.IdentityFromContext(r.Context()), reports)
+	if err != nil {
+		respondWithError(w, r, err, "failed to ingest task statistics")
+		return
+	}
+
+	respondWithJSON(w, IngestTaskStatisticsResponse{
+		Ingested:       len(reports) - len(unknownTaskIds),
+		UnknownTaskIds: unknownTaskIds,
+	}, http.StatusOK)
+}
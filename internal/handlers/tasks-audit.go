@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ListTaskAuditResponse struct {
+	Events []AuditEvent `json:"events"`
+}
+
+type AuditEvent struct {
+	EventId     string        `json:"event_id"`
+	Action      string        `json:"action"`
+	Actor       string        `json:"actor"`
+	Timestamp   time.Time     `json:"timestamp"`
+	OldRevision int           `json:"old_revision"`
+	NewRevision int           `json:"new_revision"`
+	Changes     []FieldChange `json:"changes"`
+	BlobKey     string        `json:"blob_key,omitempty"`
+}
+
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+func (c *Controller) ListTaskAudit(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	events, err := c.auditSrv.ListTaskEvents(auth.IdentityFromContext(r.Context()), id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to list audit events")
+		return
+	}
+
+	res := []AuditEvent{}
+	for _, event := range events {
+		res = append(res, mapDomainAuditEventToAuditEventResponse(event))
+	}
+	respondWithJSON(w, ListTaskAuditResponse{Events: res}, http.StatusOK)
+}
+
+func mapDomainAuditEventToAuditEventResponse(event domain.AuditEvent) AuditEvent {
+	changes := []FieldChange{}
+	for _, change := range event.Changes {
+		res := FieldChange{Field: change.Field}
+		if change.Old != "" {
+			res.Old = json.RawMessage(change.Old)
+		}
+		if change.New != "" {
+			res.New = json.RawMessage(change.New)
+		}
+		changes = append(changes, res)
+	}
+
+	return AuditEvent{
+		EventId:     event.EventId,
+		Action:      string(event.Action),
+		Actor:       event.Actor,
+		Timestamp:   event.Timestamp,
+		OldRevision: event.OldRevision,
+		NewRevision: event.NewRevision,
+		Changes:     changes,
+		BlobKey:     event.BlobKey,
+	}
+}
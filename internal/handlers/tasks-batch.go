@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+type BatchUpdateTasksRequest struct {
+	Operations []TaskBatchOperation `json:"operations"`
+}
+
+type TaskBatchOperation struct {
+	TaskIds        []string `json:"task_ids"`
+	AddTags        []string `json:"add_tags"`
+	RemoveTags     []string `json:"remove_tags"`
+	Difficulty     *int     `json:"difficulty"`
+	OriginOlympiad *string  `json:"origin_olympiad"`
+	State          *string  `json:"state"`
+}
+
+type BatchUpdateTasksResponse struct {
+	Results []TaskBatchResult `json:"results"`
+}
+
+type TaskBatchResult struct {
+	TaskId   string `json:"task_id"`
+	Ok       bool   `json:"ok"`
+	Revision int    `json:"revision,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (c *Controller) BatchUpdateTasks(w http.ResponseWriter, r *http.Request) {
+	var req BatchUpdateTasksRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	operations := make([]service.TaskBatchOperation, 0, len(req.Operations))
+	for _, operation := range req.Operations {
+		patch := service.TaskPatch{
+			AddTags:        operation.AddTags,
+			RemoveTags:     operation.RemoveTags,
+			Difficulty:     operation.Difficulty,
+			OriginOlympiad: operation.OriginOlympiad,
+		}
+		if operation.State != nil {
+			state := domain.TaskState(*operation.State)
+			patch.State = &state
+		}
+		operations = append(operations, service.TaskBatchOperation{
+			TaskIds: operation.TaskIds,
+			Patch:   patch,
+		})
+	}
+
+	results, err := c.taskSrv.BatchUpdateTasks(auth.IdentityFromContext(r.Context()), operations)
+	if err != nil {
+		respondWithError(w, r, err, "failed to update tasks")
+		return
+	}
+
+	response := BatchUpdateTasksResponse{Results: make([]TaskBatchResult, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, mapTaskBatchResultToResponse(r, result))
+	}
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+func mapTaskBatchResultToResponse(r *http.Request, result service.TaskBatchResult) TaskBatchResult {
+	if result.Err == nil {
+		return TaskBatchResult{
+			TaskId:   result.TaskId,
+			Ok:       true,
+			Revision: result.Task.GetRevision(),
+		}
+	}
+
+	msg := "failed to update task"
+	var domainErr *domain.DomainError
+	if errors.As(result.Err, &domainErr) {
+		msg = localizedErrorMessage(r, domainErr)
+	} else {
+		log.Printf("%s %s: %v", msg, result.TaskId, result.Err)
+	}
+	return TaskBatchResult{TaskId: result.TaskId, Error: msg}
+}
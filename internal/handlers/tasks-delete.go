@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+)
+
+func (c *Controller) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	err := c.taskSrv.DeleteTask(auth.IdentityFromContext(r.Context()), id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to delete task")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
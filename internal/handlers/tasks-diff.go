@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/taskdiff"
+	"github.com/programme-lv/tasks-microservice/internal/textdiff"
+)
+
+type GetTaskDiffResponse struct {
+	Diff TaskDiff `json:"diff"`
+}
+
+type TaskDiff struct {
+	PublishedTaskId string          `json:"published_task_id"`
+	FromRevision    int             `json:"from_revision"`
+	ToRevision      int             `json:"to_revision"`
+	Fields          []DiffField     `json:"fields"`
+	Tests           []DiffTest      `json:"tests"`
+	TestGroups      []DiffTestGroup `json:"test_groups"`
+	Statements      []DiffStatement `json:"statements"`
+}
+
+type DiffField struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type DiffTest struct {
+	TestId          int64  `json:"test_id"`
+	Change          string `json:"change"`
+	OldInputSha256  string `json:"old_input_sha256,omitempty"`
+	OldAnswerSha256 string `json:"old_answer_sha256,omitempty"`
+	NewInputSha256  string `json:"new_input_sha256,omitempty"`
+	NewAnswerSha256 string `json:"new_answer_sha256,omitempty"`
+}
+
+type DiffTestGroup struct {
+	GroupId        int    `json:"group_id"`
+	Change         string `json:"change"`
+	OldPoints      int    `json:"old_points"`
+	NewPoints      int    `json:"new_points"`
+	AddedTestIds   []int  `json:"added_test_ids"`
+	RemovedTestIds []int  `json:"removed_test_ids"`
+}
+
+type DiffStatement struct {
+	Language string     `json:"language"`
+	Section  string     `json:"section"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"` // "equal", "insert" or "delete"
+	Text string `json:"text"`
+}
+
+func (c *Controller) GetTaskDiff(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	revisions := map[string]int{"from": 0, "to": 0}
+	for param := range revisions {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		revision, err := strconv.Atoi(value)
+		if err != nil || revision <= 0 {
+			respondWithJSON(w, "invalid "+param+" revision", http.StatusBadRequest)
+			return
+		}
+		revisions[param] = revision
+	}
+
+	diff, err := c.taskSrv.DiffTask(id, revisions["from"], revisions["to"])
+	if err != nil {
+		respondWithError(w, r, err, "failed to diff task")
+		return
+	}
+
+	respondWithJSON(w, GetTaskDiffResponse{
+		Diff: mapTaskDiffToTaskDiffResponse(diff),
+	}, http.StatusOK)
+}
+
+func mapTaskDiffToTaskDiffResponse(diff *taskdiff.Diff) TaskDiff {
+	fields := make([]DiffField, 0)
+	for _, field := range diff.Fields {
+		fields = append(fields, DiffField{
+			Field: field.Field,
+			Old:   field.Old,
+			New:   field.New,
+		})
+	}
+
+	tests := make([]DiffTest, 0)
+	for _, test := range diff.Tests {
+		tests = append(tests, DiffTest{
+			TestId:          test.TestId,
+			Change:          string(test.Kind),
+			OldInputSha256:  test.OldInputSha256,
+			OldAnswerSha256: test.OldAnswerSha256,
+			NewInputSha256:  test.NewInputSha256,
+			NewAnswerSha256: test.NewAnswerSha256,
+		})
+	}
+
+	testGroups := make([]DiffTestGroup, 0)
+	for _, group := range diff.TestGroups {
+		testGroups = append(testGroups, DiffTestGroup{
+			GroupId:        group.GroupId,
+			Change:         string(group.Kind),
+			OldPoints:      group.OldPoints,
+			NewPoints:      group.NewPoints,
+			AddedTestIds:   group.AddedTestIds,
+			RemovedTestIds: group.RemovedTestIds,
+		})
+	}
+
+	ops := map[textdiff.Op]string{
+		textdiff.OpEqual:  "equal",
+		textdiff.OpInsert: "insert",
+		textdiff.OpDelete: "delete",
+	}
+	statements := make([]DiffStatement, 0)
+	for _, statement := range diff.Statements {
+		lines := make([]DiffLine, 0, len(statement.Lines))
+		for _, line := range statement.Lines {
+			lines = append(lines, DiffLine{Op: ops[line.Op], Text: line.Text})
+		}
+		statements = append(statements, DiffStatement{
+			Language: statement.Language,
+			Section:  statement.Section,
+			Lines:    lines,
+		})
+	}
+
+	return TaskDiff{
+		PublishedTaskId: diff.TaskId,
+		FromRevision:    diff.FromRevision,
+		ToRevision:      diff.ToRevision,
+		Fields:          fields,
+		Tests:           tests,
+		TestGroups:      testGroups,
+		Statements:      statements,
+	}
+}
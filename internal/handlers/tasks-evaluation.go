@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type GetTaskEvaluationResponse struct {
+	Evaluation TaskEvaluation `json:"evaluation"`
+}
+
+// TaskEvaluation describes how submissions are evaluated. The endpoint
+// is public, so test input and answer hashes are left out: with them
+// contestants could check their output-only answers offline.
+type TaskEvaluation struct {
+	PublishedTaskId string                  `json:"published_task_id"`
+	Type            string                  `json:"type"`
+	FloatTolerance  float64                 `json:"float_tolerance,omitempty"`
+	Checker         *SourceFile             `json:"checker,omitempty"`
+	Interactor      *SourceFile             `json:"interactor,omitempty"`
+	Graders         map[string][]SourceFile `json:"graders"`
+	Limits          Limits                  `json:"limits"`
+	LanguageLimits  map[string]Limits       `json:"language_limits"`
+	Tests           []EvaluationTest        `json:"tests"`
+	TestGroups      []EvaluationGroup       `json:"test_groups"`
+}
+
+type SourceFile struct {
+	Language string `json:"language"`
+	FileName string `json:"file_name,omitempty"`
+	Sha256   string `json:"sha256"`
+}
+
+type EvaluationTest struct {
+	TestId int64 `json:"test_id"`
+}
+
+// EvaluationGroup is a test group. The evaluator awards its points
+// according to Scoring, one of the domain.ScoringPolicy values.
+type EvaluationGroup struct {
+	GroupId    int    `json:"group_id"`
+	Points     int    `json:"points"`
+	Public     bool   `json:"public"`
+	SubtaskIds []int  `json:"subtask_ids"`
+	TestIds    []int  `json:"test_ids"`
+	Scoring    string `json:"scoring"`
+}
+
+func (c *Controller) GetTaskEvaluation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	task, err := c.taskSrv.GetTask(id, canPreviewUnpublished(r))
+	if err != nil {
+		respondWithJSON(w, "task not found", http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, GetTaskEvaluationResponse{
+		Evaluation: mapDomainTaskToTaskEvaluationResponse(task),
+	}, http.StatusOK)
+}
+
+func mapDomainTaskToTaskEvaluationResponse(task *domain.Task) TaskEvaluation {
+	tests := make([]EvaluationTest, 0)
+	for _, test := range task.GetTests() {
+		tests = append(tests, EvaluationTest{TestId: test.TestId})
+	}
+
+	testGroups := make([]EvaluationGroup, 0)
+	for _, group := range task.GetTestGroups() {
+		testGroups = append(testGroups, EvaluationGroup{
+			GroupId:    group.GroupId,
+			Points:     group.Points,
+			Public:     group.Public,
+			SubtaskIds: group.SubtaskIds,
+			TestIds:    group.TestIds,
+			Scoring:    string(group.GetScoring()),
+		})
+	}
+
+	evaluation := task.GetEvaluation()
+	graders := map[string][]SourceFile{}
+	for language, files := range evaluation.Graders {
+		for _, file := range files {
+			graders[language] = append(graders[language], *mapDomainSourceRefToSourceFile(&file))
+		}
+	}
+
+	return TaskEvaluation{
+		PublishedTaskId: task.GetId(),
+		Type:            string(evaluation.Type),
+		FloatTolerance:  evaluation.FloatTolerance,
+		Checker:         mapDomainSourceRefToSourceFile(evaluation.Checker),
+		Interactor:      mapDomainSourceRefToSourceFile(evaluation.Interactor),
+		Graders:         graders,
+		Limits:          mapDomainLimitsToLimitsResponse(task.GetLimits("")),
+		LanguageLimits:  mapDomainLanguageLimitsToLimitsResponse(task),
+		Tests:           tests,
+		TestGroups:      testGroups,
+	}
+}
+
+func mapDomainSourceRefToSourceFile(ref *domain.SourceRef) *SourceFile {
+	if ref == nil {
+		return nil
+	}
+	return &SourceFile{
+		Language: ref.Language,
+		FileName: ref.FileName,
+		Sha256:   ref.Sha256,
+	}
+}
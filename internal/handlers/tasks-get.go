@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/rendering"
+	"github.com/programme-lv/tasks-microservice/internal/service"
 )
 
 type GetTaskResponse struct {
@@ -14,30 +18,84 @@ type GetTaskResponse struct {
 }
 
 type Task struct {
-	PublishedTaskId    string            `json:"published_task_id"`
-	TaskFullName       string            `json:"task_full_name"`
-	MemoryLimitMbytes  int               `json:"memory_limit_megabytes"`
-	CpuTimeLimitSecs   float64           `json:"cpu_time_limit_seconds"`
-	OriginOlympiad     string            `json:"origin_olympiad,omitempty"`
-	LvPdfStatementSha  string            `json:"lv_pdf_statement_sha,omitempty"`
-	DifficultyRating   int               `json:"difficulty_rating,omitempty"`
-	IllustrationImgUrl string            `json:"illustration_img_url,omitempty"`
-	DefaultMdStatement *MdStatement      `json:"default_md_statement,omitempty"`
-	DefaultPdfSUrl     string            `json:"default_pdf_statement_url,omitempty"`
-	Examples           []Example         `json:"examples,omitempty"`
-	OriginNotes        map[string]string `json:"origin_notes,omitempty"`
-	VisInpStInputs     []StInputs        `json:"visible_input_subtasks,omitempty"`
+	PublishedTaskId      string            `json:"published_task_id"`
+	AliasIds             []string          `json:"alias_ids,omitempty"`
+	TaskFullName         string            `json:"task_full_name"`
+	State                string            `json:"state"`
+	PublishAt            *time.Time        `json:"publish_at,omitempty"`
+	MemoryLimitMbytes    int               `json:"memory_limit_megabytes"`
+	CpuTimeLimitSecs     float64           `json:"cpu_time_limit_seconds"`
+	WallTimeLimitSecs    float64           `json:"wall_time_limit_seconds"`
+	StackLimitMbytes     int               `json:"stack_limit_megabytes"`
+	LanguageLimits       map[string]Limits `json:"language_limits,omitempty"`
+	OriginOlympiad       string            `json:"origin_olympiad,omitempty"`
+	OriginOlympiadId     string            `json:"origin_olympiad_id,omitempty"`
+	LvPdfStatementSha    string            `json:"lv_pdf_statement_sha,omitempty"`
+	DifficultyRating     int               `json:"difficulty_rating,omitempty"`
+	IllustrationImgUrl   string            `json:"illustration_img_url,omitempty"`
+	DefaultMdStatement   *MdStatement      `json:"default_md_statement,omitempty"`
+	DefaultHtmlStatement *MdStatement      `json:"default_html_statement,omitempty"`
+	DefaultPdfSUrl       string            `json:"default_pdf_statement_url,omitempty"`
+	PdfStatements        []PdfStatement    `json:"pdf_statements"`
+	Examples             []Example         `json:"examples,omitempty"`
+	ExamplesBySubtask    []SubtaskExamples `json:"examples_by_subtask,omitempty"`
+	OriginNotes          map[string]string `json:"origin_notes,omitempty"`
+	VisInpStInputs       []StInputs        `json:"visible_input_subtasks,omitempty"`
+	Subtasks             []Subtask         `json:"subtasks"`
+	OutputOnly           bool              `json:"output_only"`
+	InputsArchiveUrl     string            `json:"inputs_archive_url,omitempty"`
+
+	// DifficultyEstimate is shown to editors only.
+	DifficultyEstimate *DifficultyEstimate `json:"difficulty_estimate,omitempty"`
+}
+
+type DifficultyEstimate struct {
+	CurrentDifficulty  int       `json:"current_difficulty_rating"`
+	ProposedDifficulty *int      `json:"proposed_difficulty_rating"` // null if too few attempts
+	Attempts           int       `json:"attempts"`
+	Solves             int       `json:"solves"`
+	AvgBestScore       float64   `json:"avg_best_score"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type Subtask struct {
+	Subtask      int    `json:"subtask"`
+	Points       int    `json:"points"`
+	Description  string `json:"description,omitempty"`
+	VisibleInput bool   `json:"visible_input"`
+}
+
+type Limits struct {
+	CpuTimeLimitSecs  float64 `json:"cpu_time_limit_seconds"`
+	WallTimeLimitSecs float64 `json:"wall_time_limit_seconds"`
+	MemoryLimitMbytes int     `json:"memory_limit_megabytes"`
+	StackLimitMbytes  int     `json:"stack_limit_megabytes"`
+}
+
+type PdfStatement struct {
+	Language string `json:"language"`
+	Sha256   string `json:"sha256"`
+	Url      string `json:"url,omitempty"`
 }
 
 type StInputs struct {
-	Subtask int      `json:"subtask"`
-	Inputs  []string `json:"inputs"`
+	Subtask        int    `json:"subtask"`
+	InputCount     int    `json:"input_count"`
+	TotalSizeBytes int    `json:"total_size_bytes"`
+	ArchiveUrl     string `json:"archive_url"`
 }
 
 type Example struct {
-	Input  string  `json:"input"`
-	Output string  `json:"output"`
-	MdNote *string `json:"md_note,omitempty"`
+	Input   string  `json:"input"`
+	Output  string  `json:"output"`
+	MdNote  *string `json:"md_note,omitempty"`
+	Subtask *int    `json:"subtask,omitempty"`
+	TestId  *int64  `json:"test_id,omitempty"`
+}
+
+type SubtaskExamples struct {
+	Subtask  *int      `json:"subtask"`
+	Examples []Example `json:"examples"`
 }
 
 type MdStatement struct {
@@ -46,6 +104,8 @@ type MdStatement struct {
 	Output  string  `json:"output"`
 	Notes   *string `json:"notes,omitempty"`
 	Scoring *string `json:"scoring,omitempty"`
+
+	UnresolvedImgUuids []string `json:"unresolved_img_uuids,omitempty"`
 }
 
 func (c *Controller) GetTask(w http.ResponseWriter, r *http.Request) {
@@ -57,87 +117,220 @@ func (c *Controller) GetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := c.taskSrv.GetTask(id)
+	opts, err := parseTaskResponseOptions(r)
+	if err != nil {
+		respondWithJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	task, err := c.taskSrv.GetTask(id, canPreviewUnpublished(r))
 	if err != nil {
 		respondWithJSON(w, "task not found", http.StatusNotFound)
 		return
 	}
 
-	respondWithJSON(w, GetTaskResponse{
-		Task: mapDomainTaskToTaskResponse(task, c.publicBucketCloudFrontHost),
-	}, http.StatusOK)
+	if task.GetId() != id {
+		// the task was renamed, point clients to its current id
+		canonical := *r.URL
+		canonical.Path = strings.TrimSuffix(r.URL.Path, id) + task.GetId()
+		http.Redirect(w, r, canonical.String(), http.StatusMovedPermanently)
+		return
+	}
+
+	response := GetTaskResponse{Task: c.mapDomainTaskToTaskResponse(task, opts)}
+	if canPreviewUnpublished(r) {
+		estimate, err := c.statisticsSrv.GetDifficultyEstimate(task.GetId())
+		if err != nil {
+			log.Printf("failed to get difficulty estimate of task %s: %v", task.GetId(), err)
+		} else if estimate != nil {
+			response.Task.DifficultyEstimate = mapDifficultyEstimateToResponse(task, *estimate)
+		}
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
 }
 
-func mapDomainTaskToTaskResponse(task *domain.Task, publicBucketCloudFrontHost string) Task {
-	illustrationImgUrl := ""
-	if publicBucketCloudFrontHost != "" && task.GetIllustrationImgObjKey() != "" {
-		illustrationImgUrl = fmt.Sprintf("https://%s/%s",
-			publicBucketCloudFrontHost, task.GetIllustrationImgObjKey())
+func mapDifficultyEstimateToResponse(task *domain.Task,
+	estimate service.DifficultyEstimate) *DifficultyEstimate {
+	res := &DifficultyEstimate{
+		CurrentDifficulty: task.GetDifficulty(),
+		Attempts:          estimate.Statistics.GetAttempts(),
+		Solves:            estimate.Statistics.GetSolves(),
+		AvgBestScore:      estimate.Statistics.GetAvgBestScore(),
+		UpdatedAt:         estimate.Statistics.GetUpdatedAt(),
+	}
+	if estimate.Proposed != 0 {
+		proposed := estimate.Proposed
+		res.ProposedDifficulty = &proposed
 	}
+	return res
+}
+
+type taskResponseOptions struct {
+	languages  []string
+	htmlFormat bool // render statements to HTML instead of markdown
+}
+
+func parseTaskResponseOptions(r *http.Request) (taskResponseOptions, error) {
+	opts := taskResponseOptions{languages: preferredLanguages(r)}
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "md", "markdown":
+	case "html":
+		opts.htmlFormat = true
+	default:
+		return opts, fmt.Errorf("unsupported format %q", format)
+	}
+	return opts, nil
+}
+
+func (c *Controller) mapDomainTaskToTaskResponse(task *domain.Task, opts taskResponseOptions) Task {
+	blobUrls := c.blobUrls
+	illustrationImgUrl := blobUrls.objectUrl(task.GetIllustrationImgObjKey())
 
 	examples := make([]Example, 0)
 	for _, example := range task.GetExamples() {
-		examples = append(examples, Example{
-			Input:  example.Input,
-			Output: example.Output,
-			MdNote: example.MdNote,
-		})
+		examples = append(examples, mapDomainExampleToExampleResponse(example))
 	}
 
-	mdStImgUuidToObjKey := task.GetImgUuidToObjKey()
-	mdStatement := task.GetDefaultMarkdownStatement()
-	if mdStatement != nil {
-		for _, section := range []*string{&mdStatement.Story, &mdStatement.Input, &mdStatement.Output,
-			mdStatement.Notes, mdStatement.Scoring} {
-			if section != nil {
-				for imgUuid, objKey := range mdStImgUuidToObjKey {
-					url := fmt.Sprintf("https://%s/%s", publicBucketCloudFrontHost, objKey)
-					*section = strings.ReplaceAll(*section, imgUuid, url)
-				}
-			}
+	examplesBySubtask := make([]SubtaskExamples, 0)
+	for _, group := range task.GetExamplesBySubtask() {
+		groupExamples := make([]Example, 0, len(group.Examples))
+		for _, example := range group.Examples {
+			groupExamples = append(groupExamples, mapDomainExampleToExampleResponse(example))
 		}
+		examplesBySubtask = append(examplesBySubtask, SubtaskExamples{
+			Subtask:  group.SubtaskId,
+			Examples: groupExamples,
+		})
 	}
 
 	var resMdStatement *MdStatement = nil
-	if mdStatement != nil {
-		resMdStatement = &MdStatement{
-			Story:   mdStatement.Story,
-			Input:   mdStatement.Input,
-			Output:  mdStatement.Output,
-			Notes:   mdStatement.Notes,
-			Scoring: mdStatement.Scoring,
+	var resHtmlStatement *MdStatement = nil
+	language, mdStatement := task.GetPreferredMarkdownStatement(opts.languages)
+	if mdStatement != nil && opts.htmlFormat {
+		rendered, err := c.htmlStatementRenderer.Render(task, language, mdStatement)
+		if err != nil {
+			log.Printf("failed to render statement html of task %s: %v", task.GetId(), err)
+		} else {
+			resHtmlStatement = mapRenderedStatementToMdStatement(rendered)
+		}
+	} else if mdStatement != nil {
+		rendered := c.statementRenderer.Render(mdStatement, task.GetImgUuidToObjKey())
+		resMdStatement = mapRenderedStatementToMdStatement(rendered)
+	}
+	for _, st := range []*MdStatement{resMdStatement, resHtmlStatement} {
+		if st != nil && len(st.UnresolvedImgUuids) > 0 {
+			log.Printf("unresolved statement images of task %s: %v",
+				task.GetId(), st.UnresolvedImgUuids)
 		}
 	}
 
 	defaultPdfStatementUrl := ""
-	if publicBucketCloudFrontHost != "" && task.GetLvOrOtherPdfSha256() != "" {
-		defaultPdfStatementUrl = fmt.Sprintf(
-			"https://%s/task-pdf-statements/%s.pdf",
-			publicBucketCloudFrontHost, task.GetLvOrOtherPdfSha256())
+	if pdf := task.GetPreferredPdfStatement(opts.languages); pdf != nil {
+		defaultPdfStatementUrl = blobUrls.pdfStatementUrl(pdf.Sha256)
+	}
+
+	pdfStatements := make([]PdfStatement, 0)
+	for _, pdf := range task.GetPdfStatementSha256s() {
+		pdfStatements = append(pdfStatements, PdfStatement{
+			Language: pdf.Language,
+			Sha256:   pdf.Sha256,
+			Url:      blobUrls.pdfStatementUrl(pdf.Sha256),
+		})
 	}
 
 	visInpStInputs := make([]StInputs, 0)
 	for _, visInpSt := range task.GetVisInpStInputs() {
-		visInpSt := StInputs{
-			Subtask: visInpSt.Subtask,
-			Inputs:  visInpSt.Inputs,
-		}
-		visInpStInputs = append(visInpStInputs, visInpSt)
+		visInpStInputs = append(visInpStInputs, StInputs{
+			Subtask:        visInpSt.Subtask,
+			InputCount:     len(visInpSt.Inputs),
+			TotalSizeBytes: visInpSt.TotalSizeBytes(),
+			ArchiveUrl: fmt.Sprintf("/tasks/%s/subtasks/%d/inputs.zip",
+				task.GetId(), visInpSt.Subtask),
+		})
 	}
 
+	subtasks := make([]Subtask, 0)
+	for _, subtask := range task.GetSubtasks() {
+		subtasks = append(subtasks, Subtask{
+			Subtask:      subtask.SubtaskId,
+			Points:       task.GetSubtaskPoints(subtask.SubtaskId),
+			Description:  localize(subtask.Descriptions, opts.languages),
+			VisibleInput: task.IsVisibleInputSubtask(subtask.SubtaskId),
+		})
+	}
+
+	inputsArchiveUrl := ""
+	if task.IsOutputOnly() {
+		inputsArchiveUrl = fmt.Sprintf("/tasks/%s/inputs.zip", task.GetId())
+	}
+
+	limits := task.GetLimits("")
 	return Task{
-		PublishedTaskId:    task.GetId(),
-		TaskFullName:       task.GetTaskFullName(),
-		MemoryLimitMbytes:  task.GetMemoryLimitMBytes(),
-		CpuTimeLimitSecs:   task.GetCpuTimeLimitSecs(),
-		OriginOlympiad:     task.GetOriginOlympiad(),
-		LvPdfStatementSha:  task.GetLvOrOtherPdfSha256(),
-		DifficultyRating:   task.GetDifficulty(),
-		IllustrationImgUrl: illustrationImgUrl,
-		DefaultMdStatement: resMdStatement,
-		DefaultPdfSUrl:     defaultPdfStatementUrl,
-		Examples:           examples,
-		OriginNotes:        task.GetOriginNotes(),
-		VisInpStInputs:     visInpStInputs,
+		PublishedTaskId:      task.GetId(),
+		AliasIds:             task.GetAliasIds(),
+		TaskFullName:         task.GetTaskFullName(),
+		State:                string(task.GetState()),
+		PublishAt:            task.GetPublishAt(),
+		MemoryLimitMbytes:    task.GetMemoryLimitMBytes(),
+		CpuTimeLimitSecs:     limits.CpuTimeSecs,
+		WallTimeLimitSecs:    limits.WallTimeSecs,
+		StackLimitMbytes:     task.GetStackLimitMBytes(),
+		LanguageLimits:       mapDomainLanguageLimitsToLimitsResponse(task),
+		OriginOlympiad:       task.GetOriginOlympiad(),
+		OriginOlympiadId:     task.GetOriginOlympiadId(),
+		LvPdfStatementSha:    task.GetLvOrOtherPdfSha256(),
+		DifficultyRating:     task.GetDifficulty(),
+		IllustrationImgUrl:   illustrationImgUrl,
+		DefaultMdStatement:   resMdStatement,
+		DefaultHtmlStatement: resHtmlStatement,
+		DefaultPdfSUrl:       defaultPdfStatementUrl,
+		PdfStatements:        pdfStatements,
+		Examples:             examples,
+		ExamplesBySubtask:    examplesBySubtask,
+		OriginNotes:          task.GetOriginNotes(),
+		VisInpStInputs:       visInpStInputs,
+		Subtasks:             subtasks,
+		OutputOnly:           task.IsOutputOnly(),
+		InputsArchiveUrl:     inputsArchiveUrl,
+	}
+}
+
+func mapRenderedStatementToMdStatement(rendered rendering.RenderedStatement) *MdStatement {
+	return &MdStatement{
+		Story:   rendered.Statement.Story,
+		Input:   rendered.Statement.Input,
+		Output:  rendered.Statement.Output,
+		Notes:   rendered.Statement.Notes,
+		Scoring: rendered.Statement.Scoring,
+
+		UnresolvedImgUuids: rendered.UnresolvedImgUuids,
+	}
+}
+
+func mapDomainExampleToExampleResponse(example domain.Example) Example {
+	return Example{
+		Input:   example.Input,
+		Output:  example.Output,
+		MdNote:  example.MdNote,
+		Subtask: example.SubtaskId,
+		TestId:  example.TestId,
+	}
+}
+
+func mapDomainLimitsToLimitsResponse(limits domain.ResourceLimits) Limits {
+	return Limits{
+		CpuTimeLimitSecs:  limits.CpuTimeSecs,
+		WallTimeLimitSecs: limits.WallTimeSecs,
+		MemoryLimitMbytes: limits.MemoryMBytes,
+		StackLimitMbytes:  limits.StackMBytes,
+	}
+}
+
+func mapDomainLanguageLimitsToLimitsResponse(task *domain.Task) map[string]Limits {
+	res := map[string]Limits{}
+	for language := range task.GetLimitOverrides() {
+		res[language] = mapDomainLimitsToLimitsResponse(task.GetLimits(language))
 	}
+	return res
 }
@@ -3,6 +3,8 @@ package handlers
 import (
 	"log"
 	"net/http"
+
+	"github.com/programme-lv/tasks-microservice/internal/service"
 )
 
 type ListTasksResponse struct {
@@ -10,16 +12,34 @@ type ListTasksResponse struct {
 }
 
 func (c *Controller) ListTasks(w http.ResponseWriter, r *http.Request) {
-	domainTaskObjs, err := c.taskSrv.ListTasks()
+	opts, err := parseTaskResponseOptions(r)
 	if err != nil {
-		log.Println("failed to list tasks", "error", err)
+		respondWithJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	domainTaskObjs, err := c.taskSrv.ListTasks(canPreviewUnpublished(r))
+	if err != nil {
+		log.Printf("failed to list tasks: %v", err)
 		respondWithJSON(w, "failed to list tasks", http.StatusInternalServerError)
 		return
 	}
 
+	estimates := map[string]service.DifficultyEstimate{}
+	if canPreviewUnpublished(r) {
+		estimates, err = c.statisticsSrv.ListDifficultyEstimates()
+		if err != nil {
+			log.Printf("failed to list difficulty estimates: %v", err)
+		}
+	}
+
 	tasks := []Task{}
 	for _, task := range domainTaskObjs {
-		tasks = append(tasks, mapDomainTaskToTaskResponse(&task, c.publicBucketCloudFrontHost))
+		mapped := c.mapDomainTaskToTaskResponse(&task, opts)
+		if estimate, ok := estimates[task.GetId()]; ok {
+			mapped.DifficultyEstimate = mapDifficultyEstimateToResponse(&task, estimate)
+		}
+		tasks = append(tasks, mapped)
 	}
 	respondWithJSON(w, ListTasksResponse{
 		Tasks: tasks,
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+)
+
+type SetTaskOwnersRequest struct {
+	OwnerIds []string `json:"owner_ids"`
+}
+
+type TaskOwnersResponse struct {
+	PublishedTaskId string   `json:"published_task_id"`
+	OwnerIds        []string `json:"owner_ids"`
+}
+
+func (c *Controller) SetTaskOwners(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	var req SetTaskOwnersRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.OwnerIds == nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := c.taskSrv.SetTaskOwners(auth.IdentityFromContext(r.Context()), id, req.OwnerIds)
+	if err != nil {
+		respondWithError(w, r, err, "failed to set task owners")
+		return
+	}
+
+	respondWithJSON(w, TaskOwnersResponse{
+		PublishedTaskId: task.GetId(),
+		OwnerIds:        task.GetOwnerIds(),
+	}, http.StatusOK)
+}
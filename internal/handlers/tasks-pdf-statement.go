@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTaskPdfStatement redirects to the pdf statement of the task in
+// the language negotiated through the "lang" query parameter or the
+// Accept-Language header.
+func (c *Controller) GetTaskPdfStatement(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	task, err := c.taskSrv.GetTask(id, canPreviewUnpublished(r))
+	if err != nil {
+		respondWithJSON(w, "task not found", http.StatusNotFound)
+		return
+	}
+
+	pdf := task.GetPreferredPdfStatement(preferredLanguages(r))
+	if pdf == nil {
+		respondWithJSON(w, "task has no pdf statement", http.StatusNotFound)
+		return
+	}
+
+	url := c.blobUrls.pdfStatementUrl(pdf.Sha256)
+	if url == "" {
+		respondWithJSON(w, "pdf statement url is not configured", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusFound)
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+)
+
+func (c *Controller) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	task, err := c.taskSrv.RestoreTask(auth.IdentityFromContext(r.Context()), id)
+	if err != nil {
+		respondWithError(w, r, err, "failed to restore task")
+		return
+	}
+
+	respondWithJSON(w, TaskStateResponse{
+		PublishedTaskId: task.GetId(),
+		State:           string(task.GetState()),
+		PublishAt:       task.GetPublishAt(),
+	}, http.StatusOK)
+}
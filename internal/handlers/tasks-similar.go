@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/service"
+)
+
+type ScoredTasksResponse struct {
+	Tasks []ScoredTask `json:"tasks"`
+}
+
+type ScoredTask struct {
+	Task  Task    `json:"task"`
+	Score float64 `json:"score"`
+}
+
+func (c *Controller) ListSimilarTasks(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if param := r.URL.Query().Get("limit"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed <= 0 {
+			respondWithJSON(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	opts, err := parseTaskResponseOptions(r)
+	if err != nil {
+		respondWithJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scored, err := c.recommendationSrv.SimilarTasks(id, limit)
+	if err != nil {
+		respondWithError(w, r, err, "failed to find similar tasks")
+		return
+	}
+
+	respondWithJSON(w, c.mapScoredTasksToResponse(scored, opts), http.StatusOK)
+}
+
+func (c *Controller) mapScoredTasksToResponse(scored []service.ScoredTask,
+	opts taskResponseOptions) ScoredTasksResponse {
+	res := ScoredTasksResponse{Tasks: make([]ScoredTask, 0, len(scored))}
+	for _, scoredTask := range scored {
+		res.Tasks = append(res.Tasks, ScoredTask{
+			Task:  c.mapDomainTaskToTaskResponse(&scoredTask.Task, opts),
+			Score: math.Round(scoredTask.Score*1000) / 1000,
+		})
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ChangeTaskStateRequest struct {
+	State     string     `json:"state"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+type TaskStateResponse struct {
+	PublishedTaskId string     `json:"published_task_id"`
+	State           string     `json:"state"`
+	PublishAt       *time.Time `json:"publish_at,omitempty"`
+}
+
+func (c *Controller) ChangeTaskState(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	var req ChangeTaskStateRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondWithJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := c.taskSrv.ChangeTaskState(auth.IdentityFromContext(r.Context()), id,
+		domain.TaskState(req.State), req.PublishAt)
+	if err != nil {
+		respondWithError(w, r, err, "failed to change task state")
+		return
+	}
+
+	respondWithJSON(w, TaskStateResponse{
+		PublishedTaskId: task.GetId(),
+		State:           string(task.GetState()),
+		PublishAt:       task.GetPublishAt(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetVisibleInputsZip sends the visible inputs of a subtask as a zip
+// archive of numbered files.
+func (c *Controller) GetVisibleInputsZip(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	subtask, err := strconv.Atoi(chi.URLParam(r, "st"))
+	if id == "" || err != nil {
+		respondWithJSON(w, "invalid task id or subtask", http.StatusBadRequest)
+		return
+	}
+
+	inputs, err := c.visibleInputSrv.GetVisibleInputs(id, subtask, canPreviewUnpublished(r))
+	if err != nil {
+		respondWithError(w, r, err, "failed to get visible inputs")
+		return
+	}
+
+	width := len(strconv.Itoa(len(inputs)))
+	archive, err := buildInputsZip(len(inputs), func(i int) (string, []byte, error) {
+		content, err := c.visibleInputSrv.ReadVisibleInput(inputs[i])
+		return fmt.Sprintf("%0*d.in", width, i+1), content, err
+	})
+	if err != nil {
+		log.Printf("failed to build visible inputs zip of task %s: %v", id, err)
+		respondWithJSON(w, "failed to read visible inputs", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithZip(w, fmt.Sprintf("%s-%d-inputs.zip", id, subtask), archive)
+}
+
+// GetOutputOnlyInputsZip sends the test inputs of an output-only task
+// as a zip archive of files named by test id.
+func (c *Controller) GetOutputOnlyInputsZip(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithJSON(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+
+	tests, err := c.visibleInputSrv.GetOutputOnlyTests(id, canPreviewUnpublished(r))
+	if err != nil {
+		respondWithError(w, r, err, "failed to get task inputs")
+		return
+	}
+
+	width := 1
+	for _, test := range tests {
+		width = max(width, len(strconv.FormatInt(test.TestId, 10)))
+	}
+	archive, err := buildInputsZip(len(tests), func(i int) (string, []byte, error) {
+		content, err := c.visibleInputSrv.ReadTestInput(tests[i])
+		return fmt.Sprintf("%0*d.in", width, tests[i].TestId), content, err
+	})
+	if err != nil {
+		log.Printf("failed to build inputs zip of task %s: %v", id, err)
+		respondWithJSON(w, "failed to read task inputs", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithZip(w, fmt.Sprintf("%s-inputs.zip", id), archive)
+}
+
+// buildInputsZip reads count files with read into a zip archive. All
+// files are read before anything is sent, so that a failed read is
+// reported as an error rather than as a truncated archive.
+func buildInputsZip(count int,
+	read func(i int) (name string, content []byte, err error)) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i := 0; i < count; i++ {
+		name, content, err := read(i)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := archive.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		_, err = file.Write(content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := archive.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func respondWithZip(w http.ResponseWriter, filename string, archive []byte) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestBuildInputsZip(t *testing.T) {
+	files := []string{"1 2\n", "3 4\n", "5 6\n"}
+
+	tests := []struct {
+		name     string
+		failAt   int // index of the read that fails, -1 if none
+		wantErr  bool
+		wantFile map[string]string
+	}{
+		{
+			name:   "all inputs",
+			failAt: -1,
+			wantFile: map[string]string{
+				"1.in": "1 2\n", "2.in": "3 4\n", "3.in": "5 6\n",
+			},
+		},
+		{name: "failed read", failAt: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive, err := buildInputsZip(len(files), func(i int) (string, []byte, error) {
+				if i == tt.failAt {
+					return "", nil, fmt.Errorf("object not found")
+				}
+				return fmt.Sprintf("%d.in", i+1), []byte(files[i]), nil
+			})
+			if tt.wantErr {
+				if err == nil || archive != nil {
+					t.Fatalf("got archive of %d bytes and error %v, want only an error",
+						len(archive), err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+			if err != nil {
+				t.Fatalf("invalid zip archive: %v", err)
+			}
+			got := map[string]string{}
+			for _, file := range reader.File {
+				rc, err := file.Open()
+				if err != nil {
+					t.Fatalf("failed to open %s: %v", file.Name, err)
+				}
+				content, err := io.ReadAll(rc)
+				rc.Close()
+				if err != nil {
+					t.Fatalf("failed to read %s: %v", file.Name, err)
+				}
+				got[file.Name] = string(content)
+			}
+			if !reflect.DeepEqual(got, tt.wantFile) {
+				t.Errorf("got files %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
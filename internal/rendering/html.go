@@ -0,0 +1,305 @@
+package rendering
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// HtmlStatementRenderer renders markdown statements to sanitized HTML.
+// Math spans ($...$ and $$...$$) are kept verbatim for client-side KaTeX.
+// Rendered statements are cached per task revision and language, keeping
+// the htmlCacheSize most recently used ones.
+type HtmlStatementRenderer struct {
+	statementRenderer *StatementRenderer
+	markdown          goldmark.Markdown
+	policy            *bluemonday.Policy
+
+	mu      sync.Mutex
+	cache   map[htmlCacheKey]*list.Element // of *htmlCacheEntry
+	recency *list.List                     // most recently used first
+}
+
+const htmlCacheSize = 1000
+
+type htmlCacheKey struct {
+	taskId   string
+	language string
+}
+
+type htmlCacheEntry struct {
+	key      htmlCacheKey
+	revision int
+	rendered RenderedStatement
+}
+
+func NewHtmlStatementRenderer(statementRenderer *StatementRenderer) *HtmlStatementRenderer {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(bluemonday.SpaceSeparatedTokens).
+		OnElements("span")
+
+	return &HtmlStatementRenderer{
+		statementRenderer: statementRenderer,
+		markdown:          goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		policy:            policy,
+		cache:             map[htmlCacheKey]*list.Element{},
+		recency:           list.New(),
+	}
+}
+
+// Render returns the statement of the task in the given language
+// with every section rendered to HTML.
+func (r *HtmlStatementRenderer) Render(task *domain.Task, language string,
+	statement *domain.MarkdownStatement) (RenderedStatement, error) {
+	key := htmlCacheKey{taskId: task.GetId(), language: language}
+
+	if rendered, ok := r.cached(key, task.GetRevision()); ok {
+		return rendered, nil
+	}
+
+	resolved := r.statementRenderer.Render(statement, task.GetImgUuidToObjKey())
+
+	var err error
+	toHtml := func(markdown string) string {
+		if err != nil {
+			return ""
+		}
+		var res string
+		res, err = r.renderHtml(markdown)
+		return res
+	}
+
+	rendered := RenderedStatement{
+		Statement: domain.MarkdownStatement{
+			Story:  toHtml(resolved.Statement.Story),
+			Input:  toHtml(resolved.Statement.Input),
+			Output: toHtml(resolved.Statement.Output),
+		},
+		UnresolvedImgUuids: resolved.UnresolvedImgUuids,
+	}
+	if resolved.Statement.Notes != nil {
+		notes := toHtml(*resolved.Statement.Notes)
+		rendered.Statement.Notes = &notes
+	}
+	if resolved.Statement.Scoring != nil {
+		scoring := toHtml(*resolved.Statement.Scoring)
+		rendered.Statement.Scoring = &scoring
+	}
+	if err != nil {
+		return RenderedStatement{}, err
+	}
+
+	r.store(key, task.GetRevision(), rendered)
+	return rendered, nil
+}
+
+func (r *HtmlStatementRenderer) cached(key htmlCacheKey, revision int) (RenderedStatement, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.cache[key]
+	if !ok {
+		return RenderedStatement{}, false
+	}
+	entry := element.Value.(*htmlCacheEntry)
+	if entry.revision != revision {
+		return RenderedStatement{}, false
+	}
+	r.recency.MoveToFront(element)
+	return entry.rendered, true
+}
+
+// store caches the rendered statement, evicting the least recently
+// used one when the cache is full.
+func (r *HtmlStatementRenderer) store(key htmlCacheKey, revision int, rendered RenderedStatement) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &htmlCacheEntry{key: key, revision: revision, rendered: rendered}
+	if element, ok := r.cache[key]; ok {
+		element.Value = entry
+		r.recency.MoveToFront(element)
+		return
+	}
+	r.cache[key] = r.recency.PushFront(entry)
+	if r.recency.Len() > htmlCacheSize {
+		oldest := r.recency.Back()
+		r.recency.Remove(oldest)
+		delete(r.cache, oldest.Value.(*htmlCacheEntry).key)
+	}
+}
+
+func (r *HtmlStatementRenderer) renderHtml(markdown string) (string, error) {
+	withoutMath, mathSpans := extractMath(markdown)
+
+	var buf bytes.Buffer
+	err := r.markdown.Convert([]byte(withoutMath), &buf)
+	if err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	sanitized := r.policy.Sanitize(buf.String())
+
+	for i, span := range mathSpans {
+		var replacement string
+		if strings.HasPrefix(span, "$$") {
+			replacement = fmt.Sprintf(`<span class="math display">%s</span>`, html.EscapeString(span))
+		} else {
+			replacement = fmt.Sprintf(`<span class="math inline">%s</span>`, html.EscapeString(span))
+		}
+		sanitized = strings.Replace(sanitized, mathPlaceholder(i), replacement, 1)
+	}
+
+	return sanitized, nil
+}
+
+func mathPlaceholder(i int) string {
+	return fmt.Sprintf("KATEXMATHSPAN%dKATEXMATHSPAN", i)
+}
+
+// extractMath replaces $...$ and $$...$$ spans outside of code with
+// placeholders so that the markdown renderer leaves their contents alone.
+// As in TeX, an inline span may not start with a space after its opening
+// $ or end with one before its closing $, and a closing $ may not be
+// followed by a digit, so "from $5 to $10" has no math. Code spans,
+// fenced code and indented code blocks are copied verbatim.
+func extractMath(markdown string) (string, []string) {
+	var sb strings.Builder
+	spans := []string{}
+
+	inIndentedCode := false
+	for i := 0; i < len(markdown); {
+		if i == 0 || markdown[i-1] == '\n' {
+			lineEnd := strings.IndexByte(markdown[i:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(markdown)
+			} else {
+				lineEnd += i + 1
+			}
+			line := markdown[i:lineEnd]
+			blank := strings.TrimSpace(line) == ""
+			indented := strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+			if indented && !blank && (inIndentedCode || isAfterBlankLine(markdown, i)) {
+				// an indented code block cannot interrupt a paragraph
+				inIndentedCode = true
+				sb.WriteString(line)
+				i = lineEnd
+				continue
+			}
+			if !blank {
+				inIndentedCode = false
+			}
+		}
+
+		c := markdown[i]
+		switch {
+		case c == '\\' && i+1 < len(markdown) && markdown[i+1] == '$':
+			sb.WriteString(markdown[i : i+2])
+			i += 2
+		case c == '`':
+			// copy the code span (or fence) verbatim
+			run := i
+			for run < len(markdown) && markdown[run] == '`' {
+				run++
+			}
+			fence := markdown[i:run]
+			end := strings.Index(markdown[run:], fence)
+			if end < 0 {
+				sb.WriteString(fence)
+				i = run
+				continue
+			}
+			end += run + len(fence)
+			sb.WriteString(markdown[i:end])
+			i = end
+		case c == '$':
+			delim := "$"
+			end := -1
+			if strings.HasPrefix(markdown[i:], "$$") {
+				delim = "$$"
+				end = displayMathEnd(markdown, i)
+			} else {
+				end = inlineMathEnd(markdown, i)
+			}
+			if end < 0 {
+				sb.WriteString(delim)
+				i += len(delim)
+				continue
+			}
+			sb.WriteString(mathPlaceholder(len(spans)))
+			spans = append(spans, markdown[i:end])
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+
+	return sb.String(), spans
+}
+
+// isAfterBlankLine reports whether the line starting at i
+// is the first one or follows a blank line.
+func isAfterBlankLine(markdown string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := markdown[:i-1]
+	return strings.TrimSpace(prev[strings.LastIndexByte(prev, '\n')+1:]) == ""
+}
+
+// inlineMathEnd returns the index following the $ that closes the inline
+// span opened at open, or -1 if the span is not closed in its paragraph.
+func inlineMathEnd(markdown string, open int) int {
+	if open+1 == len(markdown) || isMathSpace(markdown[open+1]) {
+		return -1
+	}
+	for j := open + 1; j < len(markdown); j++ {
+		switch markdown[j] {
+		case '\\':
+			j++
+		case '\n':
+			next := markdown[j+1:]
+			if lineEnd := strings.IndexByte(next, '\n'); lineEnd >= 0 {
+				next = next[:lineEnd]
+			}
+			if strings.TrimSpace(next) == "" {
+				return -1
+			}
+		case '$':
+			if isMathSpace(markdown[j-1]) ||
+				(j+1 < len(markdown) && markdown[j+1] >= '0' && markdown[j+1] <= '9') {
+				continue
+			}
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// displayMathEnd returns the index following the $$ that closes
+// the display span opened at open, or -1 if it is not closed.
+func displayMathEnd(markdown string, open int) int {
+	for j := open + 2; j+1 < len(markdown); j++ {
+		if markdown[j] == '\\' {
+			j++
+			continue
+		}
+		if markdown[j] == '$' && markdown[j+1] == '$' {
+			return j + 2
+		}
+	}
+	return -1
+}
+
+func isMathSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
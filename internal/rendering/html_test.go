@@ -0,0 +1,106 @@
+package rendering
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMath(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+		spans    []string
+	}{
+		{
+			name:     "inline",
+			markdown: "a $x$ b",
+			want:     "a " + mathPlaceholder(0) + " b",
+			spans:    []string{"$x$"},
+		},
+		{
+			name:     "display",
+			markdown: "$$\nx\n$$",
+			want:     mathPlaceholder(0),
+			spans:    []string{"$$\nx\n$$"},
+		},
+		{
+			name:     "currency",
+			markdown: "from $5 to $10",
+			want:     "from $5 to $10",
+			spans:    []string{},
+		},
+		{
+			name:     "space after opening",
+			markdown: "$ x$",
+			want:     "$ x$",
+			spans:    []string{},
+		},
+		{
+			name:     "space before closing",
+			markdown: "$x $",
+			want:     "$x $",
+			spans:    []string{},
+		},
+		{
+			name:     "digit after closing",
+			markdown: "$x$5",
+			want:     "$x$5",
+			spans:    []string{},
+		},
+		{
+			name:     "blank line ends inline math",
+			markdown: "$a\n\nb$",
+			want:     "$a\n\nb$",
+			spans:    []string{},
+		},
+		{
+			name:     "escaped dollar",
+			markdown: `\$x$`,
+			want:     `\$x$`,
+			spans:    []string{},
+		},
+		{
+			name:     "escaped dollar inside math",
+			markdown: `$a\$b$`,
+			want:     mathPlaceholder(0),
+			spans:    []string{`$a\$b$`},
+		},
+		{
+			name:     "code span",
+			markdown: "`$x$` $y$",
+			want:     "`$x$` " + mathPlaceholder(0),
+			spans:    []string{"$y$"},
+		},
+		{
+			name:     "fenced code",
+			markdown: "```\n$x$\n```\n$y$",
+			want:     "```\n$x$\n```\n" + mathPlaceholder(0),
+			spans:    []string{"$y$"},
+		},
+		{
+			name:     "indented code",
+			markdown: "text\n\n    $x$\n\n$y$",
+			want:     "text\n\n    $x$\n\n" + mathPlaceholder(0),
+			spans:    []string{"$y$"},
+		},
+		{
+			name:     "indented paragraph continuation",
+			markdown: "text\n    $x$",
+			want:     "text\n    " + mathPlaceholder(0),
+			spans:    []string{"$x$"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, spans := extractMath(tt.markdown)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(spans, tt.spans) {
+				t.Errorf("got spans %q, want %q", spans, tt.spans)
+			}
+		})
+	}
+}
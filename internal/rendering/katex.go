@@ -0,0 +1,78 @@
+package rendering
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+)
+
+// KatexAssets are the KaTeX stylesheet, fonts and scripts, inlined into
+// statement documents so that rendering them needs no network access.
+type KatexAssets struct {
+	Css        template.CSS
+	Script     template.JS
+	AutoRender template.JS
+}
+
+var katexFontUrl = regexp.MustCompile(`url\((fonts/[^)]+)\)`)
+
+var katexFontTypes = map[string]string{
+	".woff2": "font/woff2",
+	".woff":  "font/woff",
+	".ttf":   "font/ttf",
+}
+
+// LoadKatexAssets reads KaTeX from the dist directory of its npm
+// package, e.g. node_modules/katex/dist. The fonts the stylesheet
+// refers to are embedded in it as data URLs.
+func LoadKatexAssets(distDir string) (*KatexAssets, error) {
+	read := func(name string) (string, error) {
+		content, err := os.ReadFile(filepath.Join(distDir, name))
+		if err != nil {
+			return "", fmt.Errorf("failed to read katex asset: %w", err)
+		}
+		return string(content), nil
+	}
+
+	css, err := read("katex.min.css")
+	if err != nil {
+		return nil, err
+	}
+	script, err := read("katex.min.js")
+	if err != nil {
+		return nil, err
+	}
+	autoRender, err := read(filepath.Join("contrib", "auto-render.min.js"))
+	if err != nil {
+		return nil, err
+	}
+
+	var fontErr error
+	css = katexFontUrl.ReplaceAllStringFunc(css, func(match string) string {
+		name := katexFontUrl.FindStringSubmatch(match)[1]
+		font, err := os.ReadFile(filepath.Join(distDir, filepath.FromSlash(name)))
+		if err != nil {
+			fontErr = fmt.Errorf("failed to read katex font: %w", err)
+			return match
+		}
+		fontType, ok := katexFontTypes[path.Ext(name)]
+		if !ok {
+			fontType = "application/octet-stream"
+		}
+		return fmt.Sprintf("url(data:%s;base64,%s)", fontType,
+			base64.StdEncoding.EncodeToString(font))
+	})
+	if fontErr != nil {
+		return nil, fontErr
+	}
+
+	return &KatexAssets{
+		Css:        template.CSS(css),
+		Script:     template.JS(script),
+		AutoRender: template.JS(autoRender),
+	}, nil
+}
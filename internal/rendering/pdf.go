@@ -0,0 +1,190 @@
+package rendering
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// PdfRenderer converts a standalone HTML document to a PDF.
+type PdfRenderer interface {
+	RenderPdf(htmlDoc []byte) ([]byte, error)
+}
+
+// CommandPdfRenderer renders PDFs with a local program. The program reads
+// HTML from stdin and writes PDF to stdout, unless its arguments contain
+// the placeholders {input} and {output}, which are replaced by the paths
+// of a temporary HTML file to read and a PDF file to write, e.g.
+// "chromium --headless --print-to-pdf={output} {input}".
+type CommandPdfRenderer struct {
+	command []string
+}
+
+func NewCommandPdfRenderer(command ...string) *CommandPdfRenderer {
+	return &CommandPdfRenderer{command: command}
+}
+
+func (r *CommandPdfRenderer) RenderPdf(htmlDoc []byte) ([]byte, error) {
+	if len(r.command) == 0 {
+		return nil, fmt.Errorf("pdf renderer command is not set")
+	}
+
+	dir, err := os.MkdirTemp("", "statement-pdf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	inputPath := filepath.Join(dir, "statement.html")
+	outputPath := filepath.Join(dir, "statement.pdf")
+
+	usesFiles := false
+	args := make([]string, 0, len(r.command)-1)
+	for _, arg := range r.command[1:] {
+		replaced := strings.NewReplacer("{input}", inputPath, "{output}", outputPath).Replace(arg)
+		usesFiles = usesFiles || replaced != arg
+		args = append(args, replaced)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(r.command[0], args...)
+	cmd.Stderr = &stderr
+	if usesFiles {
+		err = os.WriteFile(inputPath, htmlDoc, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write statement document: %v", err)
+		}
+	} else {
+		cmd.Stdin = bytes.NewReader(htmlDoc)
+		cmd.Stdout = &stdout
+	}
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %v: %s", r.command[0], err, stderr.String())
+	}
+
+	pdf := stdout.Bytes()
+	if usesFiles {
+		pdf, err = os.ReadFile(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s did not produce a pdf: %v", r.command[0], err)
+		}
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		return nil, fmt.Errorf("%s did not produce a pdf", r.command[0])
+	}
+
+	return pdf, nil
+}
+
+var statementHeadings = map[string]map[string]string{
+	"lv": {"input": "Ievaddati", "output": "Izvaddati", "examples": "Piemēri",
+		"notes": "Piezīmes", "scoring": "Vērtēšana"},
+	"en": {"input": "Input", "output": "Output", "examples": "Examples",
+		"notes": "Notes", "scoring": "Scoring"},
+}
+
+var statementDocTemplate = template.Must(template.New("statement").Parse(`<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{.Katex.Css}}</style>
+<script>{{.Katex.Script}}</script>
+<script>{{.Katex.AutoRender}}</script>
+<style>
+body { font-family: serif; max-width: 40em; margin: auto; }
+pre { background: #f4f4f4; padding: 0.5em; }
+table.example { width: 100%; border-collapse: collapse; }
+table.example td { vertical-align: top; border: 1px solid #999; width: 50%; }
+img { max-width: 100%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{.Story}}
+<h2>{{index .Headings "input"}}</h2>
+{{.Input}}
+<h2>{{index .Headings "output"}}</h2>
+{{.Output}}
+{{if .Examples}}<h2>{{index .Headings "examples"}}</h2>
+{{range $i, $ex := .Examples}}<table class="example">
+<tr><td><pre>{{$ex.Input}}</pre></td><td><pre>{{$ex.Output}}</pre></td></tr>
+</table>
+{{if $ex.Note}}{{$ex.Note}}{{end}}
+{{end}}{{end}}
+{{if .Notes}}<h2>{{index .Headings "notes"}}</h2>
+{{.Notes}}{{end}}
+{{if .Scoring}}<h2>{{index .Headings "scoring"}}</h2>
+{{.Scoring}}{{end}}
+<script>
+document.querySelectorAll("span.math").forEach(function (span) {
+	renderMathInElement(span, {delimiters: [
+		{left: "$$", right: "$$", display: true},
+		{left: "$", right: "$", display: false}]});
+});
+</script>
+</body>
+</html>
+`))
+
+type statementDocExample struct {
+	Input  string
+	Output string
+	Note   template.HTML
+}
+
+// StatementHtmlDocument builds a printable HTML document from a task
+// statement rendered by HtmlStatementRenderer and the task's examples.
+// The document typesets its math with the given KaTeX assets.
+func (r *HtmlStatementRenderer) StatementHtmlDocument(task *domain.Task, language string,
+	rendered RenderedStatement, katex *KatexAssets) ([]byte, error) {
+	headings, ok := statementHeadings[language]
+	if !ok {
+		headings = statementHeadings["en"]
+	}
+
+	examples := []statementDocExample{}
+	for _, example := range task.GetExamples() {
+		docExample := statementDocExample{Input: example.Input, Output: example.Output}
+		if example.MdNote != nil {
+			note, err := r.renderHtml(*example.MdNote)
+			if err != nil {
+				return nil, err
+			}
+			docExample.Note = template.HTML(note)
+		}
+		examples = append(examples, docExample)
+	}
+
+	optional := func(section *string) template.HTML {
+		if section == nil {
+			return ""
+		}
+		return template.HTML(*section)
+	}
+
+	var buf bytes.Buffer
+	err := statementDocTemplate.Execute(&buf, map[string]interface{}{
+		"Language": language,
+		"Katex":    katex,
+		"Title":    task.GetTaskFullName(),
+		"Headings": headings,
+		"Story":    template.HTML(rendered.Statement.Story),
+		"Input":    template.HTML(rendered.Statement.Input),
+		"Output":   template.HTML(rendered.Statement.Output),
+		"Notes":    optional(rendered.Statement.Notes),
+		"Scoring":  optional(rendered.Statement.Scoring),
+		"Examples": examples,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build statement document: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+// Package rendering turns domain markdown statements into
+// presentation-ready copies for clients.
+package rendering
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// StatementRenderer resolves image references in markdown statements.
+// It never modifies the statement it is given.
+type StatementRenderer struct {
+	objectUrl func(objKey string) string
+}
+
+func NewStatementRenderer(objectUrl func(objKey string) string) *StatementRenderer {
+	return &StatementRenderer{objectUrl: objectUrl}
+}
+
+type RenderedStatement struct {
+	Statement domain.MarkdownStatement
+	// UnresolvedImgUuids lists image uuids referenced by the statement
+	// that are missing from the task's image mapping.
+	UnresolvedImgUuids []string
+}
+
+// imageRegexps match the ways a statement can reference an image. Each
+// has three groups: the text before the target, the target and the text
+// after it.
+var imageRegexps = []*regexp.Regexp{
+	// ![alt](target) or ![alt](target "title")
+	regexp.MustCompile(`(!\[[^\]]*\]\(\s*)([^)\s]+)((?:\s+"[^"]*")?\s*\))`),
+	// [label]: target or [label]: <target>, used by ![alt][label]
+	regexp.MustCompile(`(?m)(^ {0,3}\[[^\]]+\]:[ \t]*<?)([^\s>]+)(>?)`),
+	// <img src="target"> or <img src='target'>
+	regexp.MustCompile(`(<img\b[^>]*?\bsrc\s*=\s*["'])([^"']+)(["'])`),
+}
+
+var imgUuidRegexp = regexp.MustCompile(
+	`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+func (r *StatementRenderer) Render(statement *domain.MarkdownStatement,
+	imgUuidToObjKey map[string]string) RenderedStatement {
+	unresolved := map[string]bool{}
+	render := func(section string) string {
+		return r.resolveImages(section, imgUuidToObjKey, unresolved)
+	}
+
+	rendered := domain.MarkdownStatement{
+		Story:  render(statement.Story),
+		Input:  render(statement.Input),
+		Output: render(statement.Output),
+	}
+	if statement.Notes != nil {
+		notes := render(*statement.Notes)
+		rendered.Notes = &notes
+	}
+	if statement.Scoring != nil {
+		scoring := render(*statement.Scoring)
+		rendered.Scoring = &scoring
+	}
+
+	unresolvedList := make([]string, 0, len(unresolved))
+	for imgUuid := range unresolved {
+		unresolvedList = append(unresolvedList, imgUuid)
+	}
+	sort.Strings(unresolvedList)
+
+	return RenderedStatement{
+		Statement:          rendered,
+		UnresolvedImgUuids: unresolvedList,
+	}
+}
+
+func (r *StatementRenderer) resolveImages(markdown string,
+	imgUuidToObjKey map[string]string, unresolved map[string]bool) string {
+	for _, imageRegexp := range imageRegexps {
+		markdown = imageRegexp.ReplaceAllStringFunc(markdown, func(image string) string {
+			parts := imageRegexp.FindStringSubmatch(image)
+			target := parts[2]
+			objKey, ok := imgUuidToObjKey[target]
+			if !ok {
+				if imgUuidRegexp.MatchString(target) {
+					unresolved[target] = true
+				}
+				return image
+			}
+			return parts[1] + r.objectUrl(objKey) + parts[3]
+		})
+	}
+	return markdown
+}
@@ -0,0 +1,91 @@
+package rendering
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestStatementRendererResolvesImages(t *testing.T) {
+	const (
+		known   = "0a1b2c3d-0000-4000-8000-000000000001"
+		unknown = "0a1b2c3d-0000-4000-8000-000000000002"
+	)
+	renderer := NewStatementRenderer(func(objKey string) string {
+		return "https://cdn/" + objKey
+	})
+	images := map[string]string{known: "task-images/a.png"}
+
+	tests := []struct {
+		name           string
+		story          string
+		want           string
+		wantUnresolved []string
+	}{
+		{
+			name:           "inline",
+			story:          "![zīmējums](" + known + ")",
+			want:           "![zīmējums](https://cdn/task-images/a.png)",
+			wantUnresolved: []string{},
+		},
+		{
+			name:           "inline with title",
+			story:          "![a](" + known + ` "virsraksts")`,
+			want:           `![a](https://cdn/task-images/a.png "virsraksts")`,
+			wantUnresolved: []string{},
+		},
+		{
+			name:           "reference definition",
+			story:          "![a][att]\n\n[att]: " + known,
+			want:           "![a][att]\n\n[att]: https://cdn/task-images/a.png",
+			wantUnresolved: []string{},
+		},
+		{
+			name:           "reference definition in angle brackets",
+			story:          "![a][att]\n\n  [att]: <" + known + `> "title"`,
+			want:           "![a][att]\n\n  [att]: <https://cdn/task-images/a.png> \"title\"",
+			wantUnresolved: []string{},
+		},
+		{
+			name:           "html image",
+			story:          `<img width="50%" src="` + known + `">`,
+			want:           `<img width="50%" src="https://cdn/task-images/a.png">`,
+			wantUnresolved: []string{},
+		},
+		{
+			name:           "html image in single quotes",
+			story:          `<img src='` + known + `' />`,
+			want:           `<img src='https://cdn/task-images/a.png' />`,
+			wantUnresolved: []string{},
+		},
+		{
+			name:           "unresolved in every form",
+			story:          "![a](" + unknown + ")\n[b]: " + unknown + "\n<img src=\"" + unknown + "\">",
+			want:           "![a](" + unknown + ")\n[b]: " + unknown + "\n<img src=\"" + unknown + "\">",
+			wantUnresolved: []string{unknown},
+		},
+		{
+			name:           "external urls are left alone",
+			story:          "![a](https://example.com/a.png)\n[b]: https://example.com",
+			want:           "![a](https://example.com/a.png)\n[b]: https://example.com",
+			wantUnresolved: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement := &domain.MarkdownStatement{Story: tt.story}
+			rendered := renderer.Render(statement, images)
+			if rendered.Statement.Story != tt.want {
+				t.Errorf("got %q, want %q", rendered.Statement.Story, tt.want)
+			}
+			if !reflect.DeepEqual(rendered.UnresolvedImgUuids, tt.wantUnresolved) {
+				t.Errorf("got unresolved %v, want %v", rendered.UnresolvedImgUuids, tt.wantUnresolved)
+			}
+			if statement.Story != tt.story {
+				t.Errorf("statement was modified")
+			}
+		})
+	}
+}
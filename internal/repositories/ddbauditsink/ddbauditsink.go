@@ -0,0 +1,147 @@
+package ddbauditsink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type dynamoDbAuditSink struct {
+	db         *dynamodb.Client
+	auditTable string
+}
+
+// eventRow is keyed by TaskID with EventID as the sort key.
+type eventRow struct {
+	TaskID      string           `dynamodbav:"TaskID"`
+	EventID     string           `dynamodbav:"EventID"`
+	Action      string           `dynamodbav:"Action"`
+	Actor       string           `dynamodbav:"Actor"`
+	Timestamp   string           `dynamodbav:"Timestamp"`
+	OldRevision int              `dynamodbav:"OldRevision"`
+	NewRevision int              `dynamodbav:"NewRevision"`
+	Changes     []fieldChangeRow `dynamodbav:"Changes"`
+	BlobKey     string           `dynamodbav:"BlobKey,omitempty"`
+}
+
+type fieldChangeRow struct {
+	Field string `dynamodbav:"Field"`
+	Old   string `dynamodbav:"Old"`
+	New   string `dynamodbav:"New"`
+}
+
+func NewDynamoDbAuditSink(db *dynamodb.Client, auditTable string) *dynamoDbAuditSink {
+	return &dynamoDbAuditSink{
+		db:         db,
+		auditTable: auditTable,
+	}
+}
+
+// RecordEvent implements service.AuditSink.
+func (s *dynamoDbAuditSink) RecordEvent(event domain.AuditEvent) error {
+	changes := []fieldChangeRow{}
+	for _, change := range event.Changes {
+		changes = append(changes, fieldChangeRow{
+			Field: change.Field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+
+	item, err := attributevalue.MarshalMap(eventRow{
+		TaskID:      event.TaskId,
+		EventID:     event.EventId,
+		Action:      string(event.Action),
+		Actor:       event.Actor,
+		Timestamp:   event.Timestamp.Format(time.RFC3339Nano),
+		OldRevision: event.OldRevision,
+		NewRevision: event.NewRevision,
+		Changes:     changes,
+		BlobKey:     event.BlobKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %v", err)
+	}
+
+	_, err = s.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(s.auditTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put audit event: %v", err)
+	}
+
+	return nil
+}
+
+// ListTaskEvents implements service.AuditSink.
+func (s *dynamoDbAuditSink) ListTaskEvents(taskId string) ([]domain.AuditEvent, error) {
+	events := []domain.AuditEvent{}
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := s.db.Query(context.Background(), &dynamodb.QueryInput{
+			TableName:              aws.String(s.auditTable),
+			KeyConditionExpression: aws.String("TaskID = :taskId"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":taskId": &types.AttributeValueMemberS{Value: taskId},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list audit events: %v", err)
+		}
+
+		for _, item := range response.Items {
+			event, err := constructEventFromItem(item)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, *event)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return events, nil
+		}
+		startKey = response.LastEvaluatedKey
+	}
+}
+
+func constructEventFromItem(item map[string]types.AttributeValue) (*domain.AuditEvent, error) {
+	row := eventRow{}
+	err := attributevalue.UnmarshalMap(item, &row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit event: %v", err)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, row.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse audit event timestamp: %v", err)
+	}
+
+	changes := []domain.FieldChange{}
+	for _, change := range row.Changes {
+		changes = append(changes, domain.FieldChange{
+			Field: change.Field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+
+	return &domain.AuditEvent{
+		EventId:     row.EventID,
+		Action:      domain.AuditAction(row.Action),
+		Actor:       row.Actor,
+		Timestamp:   timestamp,
+		TaskId:      row.TaskID,
+		OldRevision: row.OldRevision,
+		NewRevision: row.NewRevision,
+		Changes:     changes,
+		BlobKey:     row.BlobKey,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package ddbcollectionrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type dynamoDbCollectionRepo struct {
+	db              *dynamodb.Client
+	collectionTable string
+}
+
+type collectionRow struct {
+	CollectionID string   `dynamodbav:"CollectionID"`
+	Title        string   `dynamodbav:"Title"`
+	Description  string   `dynamodbav:"Description"`
+	Owner        string   `dynamodbav:"Owner"`
+	TaskIDs      []string `dynamodbav:"TaskIDs"`
+}
+
+func NewDynamoDbCollectionRepo(db *dynamodb.Client, collectionTable string) *dynamoDbCollectionRepo {
+	return &dynamoDbCollectionRepo{
+		db:              db,
+		collectionTable: collectionTable,
+	}
+}
+
+// ListCollections implements service.CollectionRepo.
+func (r *dynamoDbCollectionRepo) ListCollections() ([]domain.Collection, error) {
+	response, err := r.db.Scan(context.Background(), &dynamodb.ScanInput{
+		TableName: aws.String(r.collectionTable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %v", err)
+	}
+
+	collections := []domain.Collection{}
+	for _, item := range response.Items {
+		collection, err := constructCollectionFromItem(item)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, *collection)
+	}
+
+	return collections, nil
+}
+
+// GetCollection implements service.CollectionRepo.
+func (r *dynamoDbCollectionRepo) GetCollection(id string) (*domain.Collection, error) {
+	response, err := r.db.GetItem(context.Background(), &dynamodb.GetItemInput{
+		Key:       collectionKey(id),
+		TableName: aws.String(r.collectionTable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %v", err)
+	}
+	if response.Item == nil {
+		return nil, domain.ErrorCollectionNotFound(id)
+	}
+
+	return constructCollectionFromItem(response.Item)
+}
+
+// SaveCollection implements service.CollectionRepo.
+func (r *dynamoDbCollectionRepo) SaveCollection(collection *domain.Collection) error {
+	item, err := attributevalue.MarshalMap(collectionRow{
+		CollectionID: collection.GetId(),
+		Title:        collection.GetTitle(),
+		Description:  collection.GetDescription(),
+		Owner:        collection.GetOwner(),
+		TaskIDs:      collection.GetTaskIds(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal collection: %v", err)
+	}
+
+	_, err = r.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.collectionTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put collection: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteCollection implements service.CollectionRepo.
+func (r *dynamoDbCollectionRepo) DeleteCollection(id string) error {
+	_, err := r.db.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		Key:       collectionKey(id),
+		TableName: aws.String(r.collectionTable),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %v", err)
+	}
+
+	return nil
+}
+
+func collectionKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"CollectionID": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func constructCollectionFromItem(item map[string]types.AttributeValue) (*domain.Collection, error) {
+	row := collectionRow{}
+	err := attributevalue.UnmarshalMap(item, &row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collection: %v", err)
+	}
+
+	collection, err := domain.NewCollection(row.CollectionID, row.Title, row.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct collection: %v", err)
+	}
+	collection.SetDescription(row.Description)
+	err = collection.SetTaskIds(row.TaskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct collection: %v", err)
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,122 @@
+package ddbolympiadrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type dynamoDbOlympiadRepo struct {
+	db            *dynamodb.Client
+	olympiadTable string
+}
+
+type olympiadRow struct {
+	OlympiadID  string            `dynamodbav:"OlympiadID"`
+	Names       map[string]string `dynamodbav:"Names"`
+	Year        int               `dynamodbav:"Year"`
+	Stage       string            `dynamodbav:"Stage"`
+	Institution string            `dynamodbav:"Institution"`
+}
+
+func NewDynamoDbOlympiadRepo(db *dynamodb.Client, olympiadTable string) *dynamoDbOlympiadRepo {
+	return &dynamoDbOlympiadRepo{
+		db:            db,
+		olympiadTable: olympiadTable,
+	}
+}
+
+// ListOlympiads implements service.OlympiadRepo.
+func (r *dynamoDbOlympiadRepo) ListOlympiads() ([]domain.Olympiad, error) {
+	olympiads := []domain.Olympiad{}
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := r.db.Scan(context.Background(), &dynamodb.ScanInput{
+			TableName:         aws.String(r.olympiadTable),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list olympiads: %v", err)
+		}
+
+		for _, item := range response.Items {
+			olympiad, err := constructOlympiadFromItem(item)
+			if err != nil {
+				return nil, err
+			}
+			olympiads = append(olympiads, *olympiad)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return olympiads, nil
+		}
+		startKey = response.LastEvaluatedKey
+	}
+}
+
+// GetOlympiad implements service.OlympiadRepo.
+func (r *dynamoDbOlympiadRepo) GetOlympiad(id string) (*domain.Olympiad, error) {
+	response, err := r.db.GetItem(context.Background(), &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"OlympiadID": &types.AttributeValueMemberS{Value: id},
+		},
+		TableName: aws.String(r.olympiadTable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get olympiad: %v", err)
+	}
+	if response.Item == nil {
+		return nil, domain.ErrorOlympiadNotFound(id)
+	}
+
+	return constructOlympiadFromItem(response.Item)
+}
+
+// SaveOlympiad implements service.OlympiadRepo.
+func (r *dynamoDbOlympiadRepo) SaveOlympiad(olympiad *domain.Olympiad) error {
+	item, err := attributevalue.MarshalMap(olympiadRow{
+		OlympiadID:  olympiad.GetId(),
+		Names:       olympiad.GetNames(),
+		Year:        olympiad.GetYear(),
+		Stage:       olympiad.GetStage(),
+		Institution: olympiad.GetInstitution(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal olympiad: %v", err)
+	}
+
+	_, err = r.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.olympiadTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put olympiad: %v", err)
+	}
+
+	return nil
+}
+
+func constructOlympiadFromItem(item map[string]types.AttributeValue) (*domain.Olympiad, error) {
+	row := olympiadRow{}
+	err := attributevalue.UnmarshalMap(item, &row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal olympiad: %v", err)
+	}
+
+	olympiad, err := domain.NewOlympiad(row.OlympiadID, row.Year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct olympiad: %v", err)
+	}
+	for language, name := range row.Names {
+		olympiad.SetName(language, name)
+	}
+	olympiad.SetStage(row.Stage)
+	olympiad.SetInstitution(row.Institution)
+
+	return olympiad, nil
+}
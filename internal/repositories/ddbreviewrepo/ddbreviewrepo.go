@@ -0,0 +1,176 @@
+package ddbreviewrepo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// dynamoDbReviewRepo keeps reviews in a table keyed by the task id
+// and the review id, so a task's reviews are read with one query.
+type dynamoDbReviewRepo struct {
+	db          *dynamodb.Client
+	reviewTable string
+}
+
+type reviewRow struct {
+	TaskID      string       `dynamodbav:"TaskID"`
+	ReviewID    string       `dynamodbav:"ReviewID"`
+	Revision    int          `dynamodbav:"Revision"`
+	SubmittedBy string       `dynamodbav:"SubmittedBy"`
+	SubmittedAt time.Time    `dynamodbav:"SubmittedAt"`
+	Status      string       `dynamodbav:"Status"`
+	Comments    []commentRow `dynamodbav:"Comments"`
+	DecidedBy   string       `dynamodbav:"DecidedBy,omitempty"`
+	DecidedAt   *time.Time   `dynamodbav:"DecidedAt,omitempty"`
+}
+
+type commentRow struct {
+	Author    string    `dynamodbav:"Author"`
+	Body      string    `dynamodbav:"Body"`
+	Language  string    `dynamodbav:"Language,omitempty"`
+	Section   string    `dynamodbav:"Section,omitempty"`
+	TestID    *int64    `dynamodbav:"TestID,omitempty"`
+	CreatedAt time.Time `dynamodbav:"CreatedAt"`
+}
+
+func NewDynamoDbReviewRepo(db *dynamodb.Client, reviewTable string) *dynamoDbReviewRepo {
+	return &dynamoDbReviewRepo{
+		db:          db,
+		reviewTable: reviewTable,
+	}
+}
+
+// GetReview implements service.ReviewRepo.
+func (r *dynamoDbReviewRepo) GetReview(taskId string, id string) (*domain.Review, error) {
+	response, err := r.db.GetItem(context.Background(), &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"TaskID":   &types.AttributeValueMemberS{Value: taskId},
+			"ReviewID": &types.AttributeValueMemberS{Value: id},
+		},
+		TableName:      aws.String(r.reviewTable),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %v", err)
+	}
+	if response.Item == nil {
+		return nil, domain.ErrorReviewNotFound(id)
+	}
+
+	return constructReviewFromItem(response.Item)
+}
+
+// ListTaskReviews implements service.ReviewRepo. The query is strongly
+// consistent, as publishing checks it for an approval just given.
+func (r *dynamoDbReviewRepo) ListTaskReviews(taskId string) ([]domain.Review, error) {
+	res := []domain.Review{}
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := r.db.Query(context.Background(), &dynamodb.QueryInput{
+			TableName:              aws.String(r.reviewTable),
+			KeyConditionExpression: aws.String("TaskID = :taskId"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":taskId": &types.AttributeValueMemberS{Value: taskId},
+			},
+			ConsistentRead:    aws.Bool(true),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query reviews: %v", err)
+		}
+
+		for _, item := range response.Items {
+			review, err := constructReviewFromItem(item)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, *review)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = response.LastEvaluatedKey
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].GetSubmittedAt().Before(res[j].GetSubmittedAt())
+	})
+	return res, nil
+}
+
+// SaveReview implements service.ReviewRepo.
+func (r *dynamoDbReviewRepo) SaveReview(review *domain.Review) error {
+	comments := []commentRow{}
+	for _, comment := range review.GetComments() {
+		comments = append(comments, commentRow{
+			Author:    comment.Author,
+			Body:      comment.Body,
+			Language:  comment.Anchor.Language,
+			Section:   comment.Anchor.Section,
+			TestID:    comment.Anchor.TestId,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	item, err := attributevalue.MarshalMap(reviewRow{
+		TaskID:      review.GetTaskId(),
+		ReviewID:    review.GetId(),
+		Revision:    review.GetRevision(),
+		SubmittedBy: review.GetSubmittedBy(),
+		SubmittedAt: review.GetSubmittedAt(),
+		Status:      string(review.GetStatus()),
+		Comments:    comments,
+		DecidedBy:   review.GetDecidedBy(),
+		DecidedAt:   review.GetDecidedAt(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal review: %v", err)
+	}
+
+	_, err = r.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.reviewTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put review: %v", err)
+	}
+
+	return nil
+}
+
+func constructReviewFromItem(item map[string]types.AttributeValue) (*domain.Review, error) {
+	row := reviewRow{}
+	err := attributevalue.UnmarshalMap(item, &row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal review: %v", err)
+	}
+
+	review := domain.NewReview(row.ReviewID, row.TaskID, row.Revision,
+		row.SubmittedBy, row.SubmittedAt)
+	review.SetDecision(domain.ReviewStatus(row.Status), row.DecidedBy, row.DecidedAt)
+
+	comments := []domain.ReviewComment{}
+	for _, comment := range row.Comments {
+		comments = append(comments, domain.ReviewComment{
+			Author: comment.Author,
+			Body:   comment.Body,
+			Anchor: domain.ReviewAnchor{
+				Language: comment.Language,
+				Section:  comment.Section,
+				TestId:   comment.TestID,
+			},
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	review.SetComments(comments)
+	return review, nil
+}
@@ -0,0 +1,118 @@
+package ddbstatsrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type dynamoDbStatisticsRepo struct {
+	db              *dynamodb.Client
+	statisticsTable string
+}
+
+type statisticsRow struct {
+	TaskID       string    `dynamodbav:"TaskID"`
+	Attempts     int       `dynamodbav:"Attempts"`
+	Solves       int       `dynamodbav:"Solves"`
+	AvgBestScore float64   `dynamodbav:"AvgBestScore"`
+	UpdatedAt    time.Time `dynamodbav:"UpdatedAt"`
+}
+
+func NewDynamoDbStatisticsRepo(db *dynamodb.Client, statisticsTable string) *dynamoDbStatisticsRepo {
+	return &dynamoDbStatisticsRepo{
+		db:              db,
+		statisticsTable: statisticsTable,
+	}
+}
+
+// GetTaskStatistics implements service.StatisticsRepo.
+func (r *dynamoDbStatisticsRepo) GetTaskStatistics(taskId string) (*domain.TaskStatistics, error) {
+	response, err := r.db.GetItem(context.Background(), &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"TaskID": &types.AttributeValueMemberS{Value: taskId},
+		},
+		TableName: aws.String(r.statisticsTable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task statistics: %v", err)
+	}
+	if response.Item == nil {
+		return nil, nil
+	}
+
+	return constructStatisticsFromItem(response.Item)
+}
+
+// ListTaskStatistics implements service.StatisticsRepo.
+func (r *dynamoDbStatisticsRepo) ListTaskStatistics() ([]domain.TaskStatistics, error) {
+	res := []domain.TaskStatistics{}
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := r.db.Scan(context.Background(), &dynamodb.ScanInput{
+			TableName:         aws.String(r.statisticsTable),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list task statistics: %v", err)
+		}
+
+		for _, item := range response.Items {
+			stats, err := constructStatisticsFromItem(item)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, *stats)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return res, nil
+		}
+		startKey = response.LastEvaluatedKey
+	}
+}
+
+// SaveTaskStatistics implements service.StatisticsRepo.
+func (r *dynamoDbStatisticsRepo) SaveTaskStatistics(stats *domain.TaskStatistics) error {
+	item, err := attributevalue.MarshalMap(statisticsRow{
+		TaskID:       stats.GetTaskId(),
+		Attempts:     stats.GetAttempts(),
+		Solves:       stats.GetSolves(),
+		AvgBestScore: stats.GetAvgBestScore(),
+		UpdatedAt:    stats.GetUpdatedAt(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal task statistics: %v", err)
+	}
+
+	_, err = r.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.statisticsTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put task statistics: %v", err)
+	}
+
+	return nil
+}
+
+func constructStatisticsFromItem(item map[string]types.AttributeValue) (*domain.TaskStatistics, error) {
+	row := statisticsRow{}
+	err := attributevalue.UnmarshalMap(item, &row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task statistics: %v", err)
+	}
+
+	stats, err := domain.NewTaskStatistics(row.TaskID, row.Attempts, row.Solves,
+		row.AvgBestScore, row.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct task statistics: %v", err)
+	}
+	return stats, nil
+}
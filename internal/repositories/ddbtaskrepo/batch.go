@@ -0,0 +1,145 @@
+package ddbtaskrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// A transaction holds at most 100 writes of at most 4 MB in total.
+// The byte limit leaves headroom for the request overhead.
+const (
+	maxTransactionItems = 100
+	maxTransactionBytes = 4_000_000
+)
+
+// SaveTasks implements service.TaskRepo. Every manifest is built and
+// validated before the first write, so a task that fails validation is
+// not saved and does not affect the others. The valid tasks are saved
+// atomically if they fit in one transaction, and otherwise in chunks
+// that each fit in one and are saved atomically. The returned slice
+// holds the error of every task that was not saved and nil for the
+// others.
+func (r *dynamoDbTaskRepo) SaveTasks(tasks []*domain.Task) []error {
+	errs := make([]error, len(tasks))
+	pending := []pendingSave{}
+	for i, task := range tasks {
+		tomlManifest, exists, err := r.taskManifest(task)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		manifest, err := marshalManifest(tomlManifest)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		puts, err := r.manifestPuts(task.GetId(), manifest, exists, task.GetRevision())
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		pending = append(pending, pendingSave{index: i, puts: puts})
+	}
+
+	for _, chunk := range chunkPendingSaves(pending) {
+		r.saveTaskChunk(tasks, chunk, errs)
+	}
+	return errs
+}
+
+// pendingSave holds the writes of the task at index of a batch.
+type pendingSave struct {
+	index int
+	puts  []types.TransactWriteItem
+}
+
+// chunkPendingSaves splits the saves into chunks that each fit in one
+// transaction. A save that does not fit in one on its own gets a chunk
+// of its own, for DynamoDB to reject.
+func chunkPendingSaves(pending []pendingSave) [][]pendingSave {
+	chunks := [][]pendingSave{}
+	chunk := []pendingSave{}
+	items, bytes := 0, 0
+	for _, save := range pending {
+		saveBytes := 0
+		for _, put := range save.puts {
+			saveBytes += itemSize(put.Put.Item)
+		}
+		if len(chunk) > 0 && (items+len(save.puts) > maxTransactionItems ||
+			bytes+saveBytes > maxTransactionBytes) {
+			chunks = append(chunks, chunk)
+			chunk, items, bytes = []pendingSave{}, 0, 0
+		}
+		chunk = append(chunk, save)
+		items += len(save.puts)
+		bytes += saveBytes
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// itemSize approximates the size DynamoDB accounts for an item: the
+// lengths of its attribute names and values. Task rows hold only
+// strings and numbers.
+func itemSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for name, value := range item {
+		size += len(name)
+		switch v := value.(type) {
+		case *types.AttributeValueMemberS:
+			size += len(v.Value)
+		case *types.AttributeValueMemberN:
+			size += len(v.Value)
+		}
+	}
+	return size
+}
+
+func (r *dynamoDbTaskRepo) saveTaskChunk(tasks []*domain.Task, chunk []pendingSave, errs []error) {
+	transactItems := []types.TransactWriteItem{}
+	itemTasks := []int{} // index of the task each item belongs to
+	for _, save := range chunk {
+		transactItems = append(transactItems, save.puts...)
+		for range save.puts {
+			itemTasks = append(itemTasks, save.index)
+		}
+	}
+
+	_, err := r.db.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		for i, reason := range canceledErr.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" && i < len(itemTasks) {
+				index := itemTasks[i]
+				errs[index] = domain.ErrorTaskModifiedConcurrently(tasks[index].GetId())
+			}
+		}
+		for _, save := range chunk {
+			if errs[save.index] == nil {
+				errs[save.index] = domain.ErrorTaskBatchAborted()
+			}
+		}
+		return
+	}
+	if err != nil {
+		for _, save := range chunk {
+			errs[save.index] = fmt.Errorf("failed to put tasks: %v", err)
+		}
+		return
+	}
+
+	for _, save := range chunk {
+		task := tasks[save.index]
+		task.SetRevision(task.GetRevision() + 1)
+	}
+}
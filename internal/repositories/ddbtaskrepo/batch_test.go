@@ -0,0 +1,79 @@
+package ddbtaskrepo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestChunkPendingSaves(t *testing.T) {
+	// save returns the writes of a task whose manifest has the given size
+	save := func(index int, manifestBytes int) pendingSave {
+		item := map[string]types.AttributeValue{
+			"PublishedID": &types.AttributeValueMemberS{Value: "t"},
+			"Manifest":    &types.AttributeValueMemberS{Value: strings.Repeat("x", manifestBytes)},
+		}
+		put := types.TransactWriteItem{Put: &types.Put{Item: item}}
+		return pendingSave{index: index, puts: []types.TransactWriteItem{put, put}}
+	}
+	saves := func(count int, manifestBytes int) []pendingSave {
+		res := []pendingSave{}
+		for i := 0; i < count; i++ {
+			res = append(res, save(i, manifestBytes))
+		}
+		return res
+	}
+
+	tests := []struct {
+		name    string
+		pending []pendingSave
+		want    []int // indexes of the tasks in each chunk
+	}{
+		{
+			name:    "none",
+			pending: []pendingSave{},
+			want:    []int{},
+		},
+		{
+			name:    "one transaction",
+			pending: saves(3, 100),
+			want:    []int{3},
+		},
+		{
+			name:    "item limit",
+			pending: saves(51, 100),
+			want:    []int{50, 1},
+		},
+		{
+			name:    "byte limit",
+			pending: saves(5, 900_000),
+			want:    []int{2, 2, 1},
+		},
+		{
+			name:    "save larger than a transaction",
+			pending: []pendingSave{save(0, 100), save(1, 3_000_000), save(2, 100)},
+			want:    []int{1, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			next := 0
+			for _, chunk := range chunkPendingSaves(tt.pending) {
+				got = append(got, len(chunk))
+				for _, s := range chunk {
+					if s.index != tt.pending[next].index {
+						t.Fatalf("got task %d, want %d", s.index, tt.pending[next].index)
+					}
+					next++
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got chunk sizes %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
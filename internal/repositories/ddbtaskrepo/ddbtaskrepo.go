@@ -2,68 +2,329 @@ package ddbtaskrepo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/pelletier/go-toml/v2"
 	"github.com/programme-lv/tasks-microservice/internal/domain"
 )
 
 type dynamoDbTaskRepo struct {
-	db        *dynamodb.Client
-	taskTable string
+	db            *dynamodb.Client
+	taskTable     string
+	revisionTable string // every stored manifest keyed by PublishedID and Revision
+}
+
+// taskRow is either a task or an alias row that redirects a former
+// task id to the current one.
+type taskRow struct {
+	PublishedID string `dynamodbav:"PublishedID"`
+	Manifest    string `dynamodbav:"Manifest,omitempty"`
+	Revision    int    `dynamodbav:"Revision,omitempty"`
+	AliasOf     string `dynamodbav:"AliasOf,omitempty"`
+}
+
+// revisionCondition makes a put of a task row fail unless the stored
+// row is still the one the caller read: no row at all for a new task,
+// a task row without a revision for a task stored before revisions were
+// kept, or the task at prevRevision otherwise. Alias rows never match.
+func revisionCondition(exists bool, prevRevision int) (*string, map[string]types.AttributeValue) {
+	if !exists {
+		return aws.String("attribute_not_exists(PublishedID)"), nil
+	}
+	if prevRevision == 0 {
+		return aws.String("attribute_exists(Manifest) AND " +
+			"attribute_not_exists(AliasOf) AND attribute_not_exists(Revision)"), nil
+	}
+	return aws.String("Revision = :prev"), map[string]types.AttributeValue{
+		":prev": &types.AttributeValueMemberN{Value: strconv.Itoa(prevRevision)},
+	}
 }
 
 // ListTasks implements service.TaskRepo.
 func (r *dynamoDbTaskRepo) ListTasks() ([]domain.Task, error) {
-	response, err := r.db.Scan(context.Background(), &dynamodb.ScanInput{
-		TableName: aws.String(r.taskTable),
-	})
-
+	rows, err := r.scanRows()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tasks: %v", err)
+		return nil, err
 	}
 
+	// a task that cannot be loaded is left out rather than failing
+	// the whole list; taskctl lint reports what is wrong with it
 	tasks := []domain.Task{}
-	for _, item := range response.Items {
-		type TaskRow struct {
-			PublishedID string `dynamodbav:"PublishedID"`
-			Manifest    string `dynamodbav:"Manifest"`
+	for _, row := range rows {
+		tomlManifest, _, err := parseManifest(row.Manifest)
+		if err != nil {
+			log.Printf("skipping unreadable manifest of task %s: %v", row.PublishedID, err)
+			continue
 		}
-		row := TaskRow{}
-		err = attributevalue.UnmarshalMap(item, &row)
+
+		task, err := constructTaskFromManifest(row.PublishedID, tomlManifest)
 		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal task: %v", err)
+			log.Printf("skipping invalid task %s: %v", row.PublishedID, err)
+			continue
 		}
+		task.SetRevision(row.Revision)
 
-		tomlManifest := TaskTomlManifest{}
-		err = toml.Unmarshal([]byte(row.Manifest), &tomlManifest)
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+func (r *dynamoDbTaskRepo) scanRows() ([]taskRow, error) {
+	rows := []taskRow{}
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := r.db.Scan(context.Background(), &dynamodb.ScanInput{
+			TableName:         aws.String(r.taskTable),
+			ExclusiveStartKey: startKey,
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal task manifest: %v", err)
+			return nil, fmt.Errorf("failed to list tasks: %v", err)
 		}
 
-		task, err := constructTaskFromManifest(row.PublishedID, &tomlManifest)
+		for _, item := range response.Items {
+			row := taskRow{}
+			err = attributevalue.UnmarshalMap(item, &row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal task: %v", err)
+			}
+			if row.AliasOf != "" {
+				continue
+			}
+			rows = append(rows, row)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return rows, nil
+		}
+		startKey = response.LastEvaluatedKey
+	}
+}
+
+func NewDynamoDbTaskRepo(db *dynamodb.Client, taskTable string,
+	revisionTable string) *dynamoDbTaskRepo {
+	return &dynamoDbTaskRepo{
+		db:            db,
+		taskTable:     taskTable,
+		revisionTable: revisionTable,
+	}
+}
+
+// GetTask implements service.TaskRepo. An alias id resolves to the task
+// it belongs to, so the returned task may have a different id.
+func (r *dynamoDbTaskRepo) GetTask(id string) (*domain.Task, error) {
+	row, tomlManifest, err := r.getManifest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := constructTaskFromManifest(row.PublishedID, tomlManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct task: %w", err)
+	}
+	task.SetRevision(row.Revision)
+
+	return task, nil
+}
+
+// GetTaskRevision implements service.TaskRepo. Revisions stored before
+// revision history was kept are not available, except the current one.
+// Revisions stored before a rename are kept under the former id.
+func (r *dynamoDbTaskRepo) GetTaskRevision(id string, revision int) (*domain.Task, error) {
+	current, err := r.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if current.GetRevision() == revision {
+		return current, nil
+	}
+
+	var row *taskRow
+	for _, storedId := range append([]string{current.GetId()}, current.GetAliasIds()...) {
+		row, err = r.getRevisionRow(storedId, revision)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct task: %v", err)
+			return nil, err
+		}
+		if row != nil {
+			break
 		}
+	}
+	if row == nil {
+		return nil, domain.ErrorTaskRevisionNotFound(id, revision)
+	}
 
-		tasks = append(tasks, *task)
+	tomlManifest, _, err := parseManifest(row.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := constructTaskFromManifest(current.GetId(), tomlManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct task: %w", err)
+	}
+	task.SetRevision(row.Revision)
+
+	return task, nil
+}
+
+// ListAllRevisions implements service.TaskRepo. Every revision is
+// returned under the id it was stored under, and the current revisions
+// are included as tasks stored before revision history was kept have
+// none in the history. Unlike ListTasks it fails on a revision that
+// cannot be loaded.
+func (r *dynamoDbTaskRepo) ListAllRevisions() ([]domain.Task, error) {
+	rows, err := r.scanRows()
+	if err != nil {
+		return nil, err
 	}
 
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := r.db.Scan(context.Background(), &dynamodb.ScanInput{
+			TableName:         aws.String(r.revisionTable),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list task revisions: %v", err)
+		}
+
+		for _, item := range response.Items {
+			row := taskRow{}
+			err = attributevalue.UnmarshalMap(item, &row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal task revision: %v", err)
+			}
+			rows = append(rows, row)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = response.LastEvaluatedKey
+	}
+
+	tasks := []domain.Task{}
+	for _, row := range rows {
+		tomlManifest, _, err := parseManifest(row.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("task %s revision %d: %v", row.PublishedID, row.Revision, err)
+		}
+
+		task, err := constructTaskFromManifest(row.PublishedID, tomlManifest)
+		if err != nil {
+			return nil, fmt.Errorf("task %s revision %d: %v", row.PublishedID, row.Revision, err)
+		}
+		task.SetRevision(row.Revision)
+
+		tasks = append(tasks, *task)
+	}
 	return tasks, nil
 }
 
-func NewDynamoDbTaskRepo(db *dynamodb.Client, taskTable string) *dynamoDbTaskRepo {
-	return &dynamoDbTaskRepo{
-		db:        db,
-		taskTable: taskTable,
+func (r *dynamoDbTaskRepo) getRevisionRow(id string, revision int) (*taskRow, error) {
+	response, err := r.db.GetItem(context.Background(), &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"PublishedID": &types.AttributeValueMemberS{Value: id},
+			"Revision":    &types.AttributeValueMemberN{Value: strconv.Itoa(revision)},
+		},
+		TableName: aws.String(r.revisionTable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task revision: %v", err)
+	}
+	if response.Item == nil {
+		return nil, nil
 	}
+
+	row := taskRow{}
+	err = attributevalue.UnmarshalMap(response.Item, &row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task revision: %v", err)
+	}
+	return &row, nil
 }
 
-func (r *dynamoDbTaskRepo) GetTask(id string) (*domain.Task, error) {
+// SaveTask implements service.TaskRepo. Manifest fields that are not
+// modelled by domain.Task are preserved from the stored manifest.
+// The save fails if the task was changed since it was read.
+func (r *dynamoDbTaskRepo) SaveTask(task *domain.Task) error {
+	tomlManifest, exists, err := r.taskManifest(task)
+	if err != nil {
+		return err
+	}
+	manifest, err := marshalManifest(tomlManifest)
+	if err != nil {
+		return err
+	}
+
+	err = r.putManifest(task.GetId(), manifest, exists, task.GetRevision())
+	if err != nil {
+		return err
+	}
+	task.SetRevision(task.GetRevision() + 1)
+
+	return nil
+}
+
+// taskManifest returns the manifest to store for the task and whether
+// the task is already stored. Only a task that is not stored yet starts
+// from an empty manifest. The manifest is checked for the problems that
+// the change introduces.
+func (r *dynamoDbTaskRepo) taskManifest(task *domain.Task) (*TaskTomlManifest, bool, error) {
+	row, tomlManifest, err := r.getManifest(task.GetId())
+	exists := true
+	var domainErr *domain.DomainError
+	if errors.As(err, &domainErr) && domainErr.StatusCode == domain.NotFoundErrorCode {
+		tomlManifest = &TaskTomlManifest{}
+		exists = false
+	} else if err != nil {
+		return nil, false, err
+	} else if row.PublishedID != task.GetId() {
+		return nil, false, domain.ErrorTaskIdTaken(task.GetId())
+	}
+
+	storedProblems := []ManifestProblem{}
+	if exists {
+		storedProblems = validateManifestFields(tomlManifest)
+	}
+	applyTaskToManifest(task, tomlManifest)
+
+	err = validateManifestChange(storedProblems, tomlManifest)
+	if err != nil {
+		return nil, false, err
+	}
+	return tomlManifest, exists, nil
+}
+
+// getManifest returns the stored row and manifest of a task, following
+// an alias row to the task it belongs to.
+func (r *dynamoDbTaskRepo) getManifest(id string) (*taskRow, *TaskTomlManifest, error) {
+	row, err := r.getRow(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if row.AliasOf != "" {
+		row, err = r.getRow(row.AliasOf)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tomlManifest, _, err := parseManifest(row.Manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return row, tomlManifest, nil
+}
+
+func (r *dynamoDbTaskRepo) getRow(id string) (*taskRow, error) {
 	response, err := r.db.GetItem(context.Background(), &dynamodb.GetItemInput{
 		Key: map[string]types.AttributeValue{
 			"PublishedID": &types.AttributeValueMemberS{Value: id},
@@ -73,27 +334,234 @@ func (r *dynamoDbTaskRepo) GetTask(id string) (*domain.Task, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task: %v", err)
 	}
-
-	type TaskRow struct {
-		PublishedID string `dynamodbav:"PublishedID"`
-		Manifest    string `dynamodbav:"Manifest"`
+	if response.Item == nil {
+		return nil, domain.ErrorTaskNotFound(id)
 	}
-	row := TaskRow{}
+
+	row := taskRow{}
 	err = attributevalue.UnmarshalMap(response.Item, &row)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal task: %v", err)
 	}
+	return &row, nil
+}
+
+// putManifest stores the manifest as the revision following prevRevision
+// and keeps a copy of it in the revision history. exists tells whether
+// the caller read the task from the table.
+func (r *dynamoDbTaskRepo) putManifest(id string, manifest string,
+	exists bool, prevRevision int) error {
+	transactItems, err := r.manifestPuts(id, manifest, exists, prevRevision)
+	if err != nil {
+		return err
+	}
 
-	tomlManifest := TaskTomlManifest{}
-	err = toml.Unmarshal([]byte(row.Manifest), &tomlManifest)
+	_, err = r.db.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		for _, reason := range canceledErr.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return domain.ErrorTaskModifiedConcurrently(id)
+			}
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal manifest: %v", err)
+		return fmt.Errorf("failed to put task: %v", err)
 	}
 
-	task, err := constructTaskFromManifest(row.PublishedID, &tomlManifest)
+	return nil
+}
+
+// RenameTask implements service.TaskRepo. The task, already renamed,
+// is stored under its new id, while the row of oldId and the rows of
+// its other aliases are turned into alias rows of the new id. All rows
+// are written in a single transaction.
+func (r *dynamoDbTaskRepo) RenameTask(oldId string, task *domain.Task) error {
+	_, tomlManifest, err := r.getManifest(oldId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct task: %v", err)
+		return err
 	}
 
-	return task, nil
+	storedProblems := validateManifestFields(tomlManifest)
+	applyTaskToManifest(task, tomlManifest)
+
+	err = validateManifestChange(storedProblems, tomlManifest)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := marshalManifest(tomlManifest)
+	if err != nil {
+		return err
+	}
+
+	newId := task.GetId()
+	item, err := attributevalue.MarshalMap(taskRow{
+		PublishedID: newId,
+		Manifest:    manifest,
+		Revision:    task.GetRevision() + 1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %v", err)
+	}
+
+	oldIdValues := map[string]types.AttributeValue{
+		":old": &types.AttributeValueMemberS{Value: oldId},
+	}
+	transactItems := []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName:                 aws.String(r.taskTable),
+				Item:                      item,
+				ConditionExpression:       aws.String("attribute_not_exists(PublishedID) OR AliasOf = :old"),
+				ExpressionAttributeValues: oldIdValues,
+			},
+		},
+		{
+			Put: &types.Put{
+				TableName: aws.String(r.revisionTable),
+				Item:      item,
+			},
+		},
+	}
+	for _, aliasId := range task.GetAliasIds() {
+		aliasItem, err := attributevalue.MarshalMap(taskRow{
+			PublishedID: aliasId,
+			AliasOf:     newId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal task alias: %v", err)
+		}
+
+		put := &types.Put{
+			TableName:                 aws.String(r.taskTable),
+			Item:                      aliasItem,
+			ConditionExpression:       aws.String("AliasOf = :old"),
+			ExpressionAttributeValues: oldIdValues,
+		}
+		if aliasId == oldId {
+			put.ConditionExpression, put.ExpressionAttributeValues =
+				revisionCondition(true, task.GetRevision())
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{Put: put})
+	}
+
+	_, err = r.db.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		for i, reason := range canceledErr.CancellationReasons {
+			if aws.ToString(reason.Code) != "ConditionalCheckFailed" {
+				continue
+			}
+			if i == 0 {
+				return domain.ErrorTaskIdTaken(newId)
+			}
+			return domain.ErrorTaskModifiedConcurrently(oldId)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rename task: %v", err)
+	}
+	task.SetRevision(task.GetRevision() + 1)
+
+	return nil
+}
+
+// DeleteTask implements service.TaskRepo. It permanently removes the
+// task together with its aliases and revision history.
+func (r *dynamoDbTaskRepo) DeleteTask(id string) error {
+	task, err := r.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	for _, storedId := range append([]string{task.GetId()}, task.GetAliasIds()...) {
+		_, err = r.db.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+			Key: map[string]types.AttributeValue{
+				"PublishedID": &types.AttributeValueMemberS{Value: storedId},
+			},
+			TableName: aws.String(r.taskTable),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete task: %v", err)
+		}
+
+		err = r.deleteRevisions(storedId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *dynamoDbTaskRepo) deleteRevisions(id string) error {
+	var startKey map[string]types.AttributeValue
+	for {
+		response, err := r.db.Query(context.Background(), &dynamodb.QueryInput{
+			TableName:              aws.String(r.revisionTable),
+			KeyConditionExpression: aws.String("PublishedID = :id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":id": &types.AttributeValueMemberS{Value: id},
+			},
+			ProjectionExpression: aws.String("PublishedID, Revision"),
+			ExclusiveStartKey:    startKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list task revisions: %v", err)
+		}
+
+		for _, item := range response.Items {
+			_, err = r.db.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+				Key: map[string]types.AttributeValue{
+					"PublishedID": item["PublishedID"],
+					"Revision":    item["Revision"],
+				},
+				TableName: aws.String(r.revisionTable),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete task revision: %v", err)
+			}
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		startKey = response.LastEvaluatedKey
+	}
+}
+
+// manifestPuts returns the writes of putManifest: the conditional put
+// of the task row followed by the put into the revision history.
+func (r *dynamoDbTaskRepo) manifestPuts(id string, manifest string,
+	exists bool, prevRevision int) ([]types.TransactWriteItem, error) {
+	item, err := attributevalue.MarshalMap(taskRow{
+		PublishedID: id,
+		Manifest:    manifest,
+		Revision:    prevRevision + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task: %v", err)
+	}
+
+	condition, conditionValues := revisionCondition(exists, prevRevision)
+	return []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName:                 aws.String(r.taskTable),
+				Item:                      item,
+				ConditionExpression:       condition,
+				ExpressionAttributeValues: conditionValues,
+			},
+		},
+		{
+			Put: &types.Put{
+				TableName: aws.String(r.revisionTable),
+				Item:      item,
+			},
+		},
+	}, nil
 }
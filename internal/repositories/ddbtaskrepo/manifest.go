@@ -2,44 +2,93 @@ package ddbtaskrepo
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/programme-lv/tasks-microservice/internal/domain"
 )
 
 type TaskTomlManifest struct {
-	TestSHA256s     []TestfileSHA256Ref     `toml:"tests_sha256s"`
+	SchemaVersion int `toml:"schema_version"`
+
+	// State is the lifecycle state. Manifests stored before lifecycle
+	// states were introduced have none and are treated as published.
+	State     string     `toml:"state,omitempty"`
+	PublishAt *time.Time `toml:"publish_at,omitempty"`
+	OwnerIDs  []string   `toml:"owner_ids,omitempty"` // users allowed to edit the task
+	AliasIDs  []string   `toml:"alias_ids,omitempty"` // former task ids
+	DeletedAt *time.Time `toml:"deleted_at,omitempty"`
+
+	TestSHA256s     []TestfileSHA256Ref     `toml:"test_sha256s"`
 	PDFSHA256s      []PDFStatemenSHA256tRef `toml:"pdf_statements_sha256s"`
 	MDStatements    []MDStatement           `toml:"md_statements"`
 	ImgUuidToObjKey map[string]string       `toml:"img_uuid_to_obj_key"`
 
-	TaskFullName    string      `toml:"task_full_name"`
-	MemoryLimMB     int         `toml:"memory_lim_megabytes"`
-	CpuTimeInSecs   float64     `toml:"cpu_time_in_seconds"`
-	ProblemTags     []string    `toml:"problem_tags"`
-	Difficulty      int         `toml:"difficulty_1_to_5"`
-	TaskAuthors     []string    `toml:"task_authors"`
-	OriginOlympiad  string      `toml:"origin_olympiad"`
-	VisibleInputSTs []int       `toml:"visible_input_subtasks"`
-	VisInpStInputs  []StInputs  `toml:"vis_inp_subtask_inputs"`
-	TestGroups      []TestGroup `toml:"test_groups"`
+	TaskFullName     string      `toml:"task_full_name"`
+	MemoryLimMB      int         `toml:"memory_lim_megabytes"`
+	CpuTimeInSecs    float64     `toml:"cpu_time_in_seconds"`
+	WallTimeInSecs   float64     `toml:"wall_time_in_seconds,omitempty"`
+	StackLimMB       int         `toml:"stack_lim_megabytes,omitempty"`
+	ProblemTags      []string    `toml:"problem_tags"`
+	Difficulty       int         `toml:"difficulty_1_to_5"`
+	TaskAuthors      []string    `toml:"task_authors"`
+	OriginOlympiad   string      `toml:"origin_olympiad"`
+	OriginOlympiadID string      `toml:"origin_olympiad_id,omitempty"`
+	VisibleInputSTs  []int       `toml:"visible_input_subtasks"`
+	VisInpStInputs   []StInputs  `toml:"vis_inp_subtask_inputs"`
+	TestGroups       []TestGroup `toml:"test_groups"`
+	Subtasks         []Subtask   `toml:"subtasks,omitempty"`
 
-	IllustrationImg string `toml:"illustration_img_s3objkey, omitempty"`
+	IllustrationImg string `toml:"illustration_img_s3objkey,omitempty"`
 
 	OriginNotes       map[string]string `toml:"origin_notes,omitempty"`
 	OriginInstitution string            `toml:"origin_institution,omitempty"`
 
 	Examples []Example `toml:"examples,omitempty"`
+
+	Limits map[string]LimitOverride `toml:"limits,omitempty"` // map[language]override
+
+	Evaluation *Evaluation `toml:"evaluation,omitempty"`
+}
+
+type Evaluation struct {
+	Type           string                 `toml:"type"`
+	FloatTolerance float64                `toml:"float_tolerance,omitempty"`
+	Checker        *SourceRef             `toml:"checker,omitempty"`
+	Interactor     *SourceRef             `toml:"interactor,omitempty"`
+	Graders        map[string][]SourceRef `toml:"graders,omitempty"` // map[language]files
+}
+
+type SourceRef struct {
+	Language string `toml:"language"`
+	FileName string `toml:"file_name"`
+	SHA256   string `toml:"sha256"`
+}
+
+type LimitOverride struct {
+	CpuMultiplier    float64 `toml:"cpu_multiplier,omitempty"`
+	MemoryMultiplier float64 `toml:"memory_multiplier,omitempty"`
 }
 
 type StInputs struct {
-	Subtask int      `toml:"subtask"`
-	Inputs  []string `toml:"inputs,multiline"`
+	Subtask   int        `toml:"subtask"`
+	Inputs    []string   `toml:"inputs,multiline"`
+	InputRefs []InputRef `toml:"input_refs,omitempty"`
+}
+
+// InputRef references a visible input stored as a test file blob.
+type InputRef struct {
+	SHA256    string `toml:"sha256"`
+	SizeBytes int    `toml:"size_bytes"`
 }
 
 type Example struct {
-	Input  string `toml:"input"`
-	Output string `toml:"output"`
-	MdNote string `toml:"md_note,omitempty"`
+	Input   string `toml:"input"`
+	Output  string `toml:"output"`
+	MdNote  string `toml:"md_note,omitempty"`
+	Subtask *int   `toml:"subtask,omitempty"`
+	TestID  *int   `toml:"test_id,omitempty"`
+	Order   int    `toml:"order,omitempty"`
 }
 
 type TestfileSHA256Ref struct {
@@ -54,11 +103,17 @@ type PDFStatemenSHA256tRef struct {
 }
 
 type TestGroup struct {
-	GroupID int   `toml:"group_id"`
-	Points  int   `toml:"points"`
-	Public  bool  `toml:"public"`
-	Subtask int   `toml:"subtask"`
-	TestIDs []int `toml:"test_ids"`
+	GroupID int    `toml:"group_id"`
+	Points  int    `toml:"points"`
+	Public  bool   `toml:"public"`
+	Subtask int    `toml:"subtask"`
+	TestIDs []int  `toml:"test_ids"`
+	Scoring string `toml:"scoring,omitempty"` // min (default), sum or sum_of_percentages
+}
+
+type Subtask struct {
+	Subtask      int               `toml:"subtask"`
+	Descriptions map[string]string `toml:"descriptions,omitempty"`
 }
 
 type MDStatement struct {
@@ -72,15 +127,50 @@ type MDStatement struct {
 
 func constructTaskFromManifest(id string, manifest *TaskTomlManifest) (
 	*domain.Task, error) {
-	task, err := domain.NewTask(id, manifest.TaskFullName)
+	task, err := domain.NewStoredTask(id, manifest.TaskFullName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %v", err)
 	}
 
-	task.SetCpuTimeLimitSecs(manifest.CpuTimeInSecs)
-	task.SetDifficulty(manifest.Difficulty)
-	task.SetMemoryLimitMBytes(manifest.MemoryLimMB)
+	// the ranges are checked on save and by lint, so that one stored task
+	// with an out of range value does not prevent reading the others
+	task.SetStoredLimits(manifest.Difficulty, manifest.MemoryLimMB, manifest.CpuTimeInSecs)
+	state := domain.TaskStatePublished
+	if manifest.State != "" {
+		state = domain.TaskState(manifest.State)
+	}
+	err = task.SetState(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set state: %w", err)
+	}
+	task.SetPublishAt(manifest.PublishAt)
+	task.SetDeletedAt(manifest.DeletedAt)
+	if manifest.OwnerIDs != nil {
+		task.SetOwnerIds(manifest.OwnerIDs)
+	}
+	if manifest.AliasIDs != nil {
+		task.SetAliasIds(manifest.AliasIDs)
+	}
+
+	err = task.SetWallTimeLimitSecs(manifest.WallTimeInSecs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set wall time limit: %w", err)
+	}
+	err = task.SetStackLimitMBytes(manifest.StackLimMB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set stack limit: %w", err)
+	}
+	for language, override := range manifest.Limits {
+		err = task.SetLimitOverride(language, domain.LimitOverride{
+			CpuMultiplier:    override.CpuMultiplier,
+			MemoryMultiplier: override.MemoryMultiplier,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set %s limits: %w", language, err)
+		}
+	}
 	task.SetOriginOlympiad(manifest.OriginOlympiad)
+	task.SetOriginOlympiadId(manifest.OriginOlympiadID)
 	task.SetProblemTags(manifest.ProblemTags)
 	task.SetTaskFullName(manifest.TaskFullName)
 	task.SetIllustrationImgObjKey(manifest.IllustrationImg)
@@ -106,24 +196,311 @@ func constructTaskFromManifest(id string, manifest *TaskTomlManifest) (
 		if example.MdNote != "" {
 			mdNotePtr = &example.MdNote
 		}
+		var testIdPtr *int64 = nil
+		if example.TestID != nil {
+			testId := int64(*example.TestID)
+			testIdPtr = &testId
+		}
 		task.AddExample(domain.Example{
-			Input:  example.Input,
-			Output: example.Output,
-			MdNote: mdNotePtr,
+			Input:     example.Input,
+			Output:    example.Output,
+			MdNote:    mdNotePtr,
+			SubtaskId: example.Subtask,
+			TestId:    testIdPtr,
+			Order:     example.Order,
 		})
 	}
 
+	tests := []domain.TestSha256Ref{}
+	for _, test := range manifest.TestSHA256s {
+		tests = append(tests, domain.TestSha256Ref{
+			TestId:       int64(test.TestID),
+			InputSha256:  test.InputSHA256,
+			AnswerSha256: test.AnswerSHA256,
+		})
+	}
+	err = task.SetTests(tests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set tests: %w", err)
+	}
+
 	for _, pdf := range manifest.PDFSHA256s {
 		task.AddPdfStatementSha256(pdf.Language, pdf.SHA256)
 	}
 
+	if manifest.Evaluation != nil {
+		err = task.SetEvaluation(constructEvaluation(manifest.Evaluation))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set evaluation: %w", err)
+		}
+	}
+
+	testGroups := []domain.TestGroup{}
+	for _, group := range manifest.TestGroups {
+		testGroups = append(testGroups, domain.TestGroup{
+			GroupId:    group.GroupID,
+			Points:     group.Points,
+			Public:     group.Public,
+			TestIds:    group.TestIDs,
+			SubtaskIds: []int{group.Subtask},
+			Scoring:    domain.ScoringPolicy(group.Scoring),
+		})
+	}
+	err = task.SetTestGroups(testGroups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set test groups: %w", err)
+	}
+	task.SetSubtasks(constructSubtasks(manifest))
+
 	for _, visInpSt := range manifest.VisibleInputSTs {
 		for _, visInpStInput := range manifest.VisInpStInputs {
 			if visInpStInput.Subtask == visInpSt {
-				task.AddVisibleInputSubtask(visInpSt, visInpStInput.Inputs)
+				inputs := []domain.VisibleInput{}
+				for _, input := range visInpStInput.Inputs {
+					inputs = append(inputs, domain.NewInlineVisibleInput(input))
+				}
+				for _, ref := range visInpStInput.InputRefs {
+					inputs = append(inputs, domain.VisibleInput{
+						Sha256:    ref.SHA256,
+						SizeBytes: ref.SizeBytes,
+					})
+				}
+				task.AddVisibleInputSubtask(visInpSt, inputs)
 			}
 		}
 	}
 
 	return task, nil
 }
+
+func constructEvaluation(evaluation *Evaluation) domain.Evaluation {
+	sourceRef := func(ref *SourceRef) *domain.SourceRef {
+		if ref == nil {
+			return nil
+		}
+		return &domain.SourceRef{
+			Language: ref.Language,
+			FileName: ref.FileName,
+			Sha256:   ref.SHA256,
+		}
+	}
+
+	res := domain.Evaluation{
+		Type:           domain.EvaluationType(evaluation.Type),
+		FloatTolerance: evaluation.FloatTolerance,
+		Checker:        sourceRef(evaluation.Checker),
+		Interactor:     sourceRef(evaluation.Interactor),
+		Graders:        map[string][]domain.SourceRef{},
+	}
+	for language, graders := range evaluation.Graders {
+		for _, grader := range graders {
+			res.Graders[language] = append(res.Graders[language], *sourceRef(&grader))
+		}
+	}
+	return res
+}
+
+func manifestEvaluation(evaluation domain.Evaluation) *Evaluation {
+	sourceRef := func(ref *domain.SourceRef) *SourceRef {
+		if ref == nil {
+			return nil
+		}
+		return &SourceRef{
+			Language: ref.Language,
+			FileName: ref.FileName,
+			SHA256:   ref.Sha256,
+		}
+	}
+
+	res := &Evaluation{
+		Type:           string(evaluation.Type),
+		FloatTolerance: evaluation.FloatTolerance,
+		Checker:        sourceRef(evaluation.Checker),
+		Interactor:     sourceRef(evaluation.Interactor),
+		Graders:        map[string][]SourceRef{},
+	}
+	for language, graders := range evaluation.Graders {
+		for _, grader := range graders {
+			res.Graders[language] = append(res.Graders[language], *sourceRef(&grader))
+		}
+	}
+	return res
+}
+
+// constructSubtasks collects the subtasks described in the manifest
+// and the ones referenced by test groups, ordered by subtask id.
+func constructSubtasks(manifest *TaskTomlManifest) []domain.Subtask {
+	subtasks := map[int]*domain.Subtask{}
+	get := func(id int) *domain.Subtask {
+		if _, ok := subtasks[id]; !ok {
+			subtasks[id] = &domain.Subtask{
+				SubtaskId:    id,
+				TestIds:      []int{},
+				Descriptions: map[string]string{},
+			}
+		}
+		return subtasks[id]
+	}
+
+	for _, st := range manifest.Subtasks {
+		subtask := get(st.Subtask)
+		for lang, description := range st.Descriptions {
+			subtask.Descriptions[lang] = description
+		}
+	}
+	for _, group := range manifest.TestGroups {
+		subtask := get(group.Subtask)
+		subtask.TestIds = append(subtask.TestIds, group.TestIDs...)
+	}
+
+	res := make([]domain.Subtask, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		res = append(res, *subtask)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].SubtaskId < res[j].SubtaskId
+	})
+	return res
+}
+
+// applyTaskToManifest overwrites the manifest fields that are modelled
+// by domain.Task, leaving the rest (tests, authors, ...) untouched.
+func applyTaskToManifest(task *domain.Task, manifest *TaskTomlManifest) {
+	manifest.State = string(task.GetState())
+	manifest.PublishAt = task.GetPublishAt()
+	manifest.OwnerIDs = task.GetOwnerIds()
+	manifest.AliasIDs = task.GetAliasIds()
+	manifest.DeletedAt = task.GetDeletedAt()
+	manifest.TaskFullName = task.GetTaskFullName()
+	manifest.MemoryLimMB = task.GetMemoryLimitMBytes()
+	manifest.CpuTimeInSecs = task.GetCpuTimeLimitSecs()
+	manifest.WallTimeInSecs = task.GetConfiguredWallTimeLimitSecs()
+	manifest.StackLimMB = task.GetConfiguredStackLimitMBytes()
+	manifest.Limits = map[string]LimitOverride{}
+	for language, override := range task.GetLimitOverrides() {
+		manifest.Limits[language] = LimitOverride{
+			CpuMultiplier:    override.CpuMultiplier,
+			MemoryMultiplier: override.MemoryMultiplier,
+		}
+	}
+	manifest.ProblemTags = task.GetProblemTags()
+	manifest.Difficulty = task.GetDifficulty()
+	manifest.OriginOlympiad = task.GetOriginOlympiad()
+	manifest.OriginOlympiadID = task.GetOriginOlympiadId()
+	manifest.IllustrationImg = task.GetIllustrationImgObjKey()
+	manifest.OriginNotes = task.GetOriginNotes()
+	manifest.ImgUuidToObjKey = task.GetImgUuidToObjKey()
+
+	manifest.MDStatements = []MDStatement{}
+	for language, mdStatement := range task.GetMarkdownStatements() {
+		var languagePtr *string = nil
+		if language != "" {
+			language := language
+			languagePtr = &language
+		}
+		manifest.MDStatements = append(manifest.MDStatements, MDStatement{
+			Language: languagePtr,
+			Story:    mdStatement.Story,
+			Input:    mdStatement.Input,
+			Output:   mdStatement.Output,
+			Notes:    mdStatement.Notes,
+			Scoring:  mdStatement.Scoring,
+		})
+	}
+	sort.Slice(manifest.MDStatements, func(i, j int) bool {
+		return mdStatementLanguage(manifest.MDStatements[i]) <
+			mdStatementLanguage(manifest.MDStatements[j])
+	})
+
+	manifest.Examples = []Example{}
+	for _, example := range task.GetExamples() {
+		mdNote := ""
+		if example.MdNote != nil {
+			mdNote = *example.MdNote
+		}
+		var testIdPtr *int = nil
+		if example.TestId != nil {
+			testId := int(*example.TestId)
+			testIdPtr = &testId
+		}
+		manifest.Examples = append(manifest.Examples, Example{
+			Input:   example.Input,
+			Output:  example.Output,
+			MdNote:  mdNote,
+			Subtask: example.SubtaskId,
+			TestID:  testIdPtr,
+			Order:   example.Order,
+		})
+	}
+
+	manifest.Evaluation = manifestEvaluation(task.GetEvaluation())
+
+	manifest.TestGroups = []TestGroup{}
+	for _, group := range task.GetTestGroups() {
+		subtask := 0
+		if len(group.SubtaskIds) > 0 {
+			subtask = group.SubtaskIds[0]
+		}
+		manifest.TestGroups = append(manifest.TestGroups, TestGroup{
+			GroupID: group.GroupId,
+			Points:  group.Points,
+			Public:  group.Public,
+			Subtask: subtask,
+			TestIDs: group.TestIds,
+			Scoring: string(group.Scoring),
+		})
+	}
+
+	manifest.Subtasks = []Subtask{}
+	for _, subtask := range task.GetSubtasks() {
+		if len(subtask.Descriptions) == 0 {
+			continue
+		}
+		manifest.Subtasks = append(manifest.Subtasks, Subtask{
+			Subtask:      subtask.SubtaskId,
+			Descriptions: subtask.Descriptions,
+		})
+	}
+
+	manifest.TestSHA256s = []TestfileSHA256Ref{}
+	for _, test := range task.GetTests() {
+		manifest.TestSHA256s = append(manifest.TestSHA256s, TestfileSHA256Ref{
+			TestID:       int(test.TestId),
+			InputSHA256:  test.InputSha256,
+			AnswerSHA256: test.AnswerSha256,
+		})
+	}
+
+	manifest.PDFSHA256s = []PDFStatemenSHA256tRef{}
+	for _, pdf := range task.GetPdfStatementSha256s() {
+		manifest.PDFSHA256s = append(manifest.PDFSHA256s, PDFStatemenSHA256tRef{
+			Language: pdf.Language,
+			SHA256:   pdf.Sha256,
+		})
+	}
+
+	manifest.VisibleInputSTs = []int{}
+	manifest.VisInpStInputs = []StInputs{}
+	for _, visInpSt := range task.GetVisInpStInputs() {
+		manifest.VisibleInputSTs = append(manifest.VisibleInputSTs, visInpSt.Subtask)
+		stInputs := StInputs{Subtask: visInpSt.Subtask, Inputs: []string{}}
+		for _, input := range visInpSt.Inputs {
+			if input.IsInline() {
+				stInputs.Inputs = append(stInputs.Inputs, *input.Content)
+				continue
+			}
+			stInputs.InputRefs = append(stInputs.InputRefs, InputRef{
+				SHA256:    input.Sha256,
+				SizeBytes: input.SizeBytes,
+			})
+		}
+		manifest.VisInpStInputs = append(manifest.VisInpStInputs, stInputs)
+	}
+}
+
+func mdStatementLanguage(mdStatement MDStatement) string {
+	if mdStatement.Language == nil {
+		return ""
+	}
+	return *mdStatement.Language
+}
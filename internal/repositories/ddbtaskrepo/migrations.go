@@ -0,0 +1,146 @@
+package ddbtaskrepo
+
+import (
+	"fmt"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// CurrentManifestSchemaVersion is the schema version written to every
+// stored manifest. Manifests without a schema_version are version 1.
+const CurrentManifestSchemaVersion = 2
+
+// manifestMigrator upgrades a raw manifest by exactly one schema version.
+type manifestMigrator func(raw map[string]interface{}) error
+
+// manifestMigrators[i] upgrades a manifest from version i+1 to i+2.
+var manifestMigrators = []manifestMigrator{
+	migrateManifestV1ToV2,
+}
+
+// migrateManifestV1ToV2 renames "tests_sha256s" to "test_sha256s".
+func migrateManifestV1ToV2(raw map[string]interface{}) error {
+	if tests, ok := raw["tests_sha256s"]; ok {
+		raw["test_sha256s"] = tests
+		delete(raw, "tests_sha256s")
+	}
+	return nil
+}
+
+// parseManifest decodes a stored manifest, upgrading it to the current
+// schema version. The returned version is the one the manifest was stored in.
+// Manifests already in the current version are decoded directly; only
+// older ones take the round trip through the migrators.
+func parseManifest(manifest string) (*TaskTomlManifest, int, error) {
+	tomlManifest := TaskTomlManifest{}
+	err := toml.Unmarshal([]byte(manifest), &tomlManifest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal manifest: %v", err)
+	}
+	if tomlManifest.SchemaVersion == CurrentManifestSchemaVersion {
+		return &tomlManifest, CurrentManifestSchemaVersion, nil
+	}
+
+	migrated, version, err := migrateManifest(manifest)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tomlManifest = TaskTomlManifest{}
+	err = toml.Unmarshal(migrated, &tomlManifest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal migrated manifest: %v", err)
+	}
+
+	return &tomlManifest, version, nil
+}
+
+// migrateManifest upgrades a manifest to the current schema version
+// and returns it re-encoded as TOML.
+func migrateManifest(manifest string) ([]byte, int, error) {
+	raw := map[string]interface{}{}
+	err := toml.Unmarshal([]byte(manifest), &raw)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal manifest: %v", err)
+	}
+
+	version := 1
+	if v, ok := raw["schema_version"].(int64); ok {
+		version = int(v)
+	}
+	if version < 1 || version > CurrentManifestSchemaVersion {
+		return nil, 0, fmt.Errorf("unsupported manifest schema version %d", version)
+	}
+
+	for v := version; v < CurrentManifestSchemaVersion; v++ {
+		err = manifestMigrators[v-1](raw)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to migrate manifest from v%d: %v", v, err)
+		}
+	}
+	raw["schema_version"] = CurrentManifestSchemaVersion
+
+	migrated, err := toml.Marshal(raw)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal migrated manifest: %v", err)
+	}
+
+	return migrated, version, nil
+}
+
+func marshalManifest(tomlManifest *TaskTomlManifest) (string, error) {
+	tomlManifest.SchemaVersion = CurrentManifestSchemaVersion
+	manifestBytes, err := toml.Marshal(tomlManifest)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	return string(manifestBytes), nil
+}
+
+type ManifestMigration struct {
+	TaskID      string
+	FromVersion int
+	Before      string
+	After       string
+}
+
+// MigrateManifests rewrites every stored manifest that is not in the
+// current schema version. The migrated manifest is written as it comes
+// out of the migrators, so keys that TaskTomlManifest does not model are
+// kept. When dryRun is set nothing is written and the would-be changes
+// are returned.
+func (r *dynamoDbTaskRepo) MigrateManifests(dryRun bool) ([]ManifestMigration, error) {
+	rows, err := r.scanRows()
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := []ManifestMigration{}
+	for _, row := range rows {
+		migrated, version, err := migrateManifest(row.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %v", row.PublishedID, err)
+		}
+		if version == CurrentManifestSchemaVersion {
+			continue
+		}
+
+		after := string(migrated)
+		migrations = append(migrations, ManifestMigration{
+			TaskID:      row.PublishedID,
+			FromVersion: version,
+			Before:      row.Manifest,
+			After:       after,
+		})
+		if dryRun {
+			continue
+		}
+
+		err = r.putManifest(row.PublishedID, after, true, row.Revision)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %v", row.PublishedID, err)
+		}
+	}
+
+	return migrations, nil
+}
@@ -0,0 +1,186 @@
+package ddbtaskrepo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+func TestMigrateManifest(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		wantVersion int
+		wantKeys    map[string]bool // top-level keys and whether they must be present
+		wantErr     string
+	}{
+		{
+			name: "v1 renames tests_sha256s",
+			manifest: `task_full_name = "Kvadrāti"
+[[tests_sha256s]]
+test_id = 1
+input_sha256 = "a"
+answer_sha256 = "b"
+`,
+			wantVersion: 1,
+			wantKeys:    map[string]bool{"test_sha256s": true, "tests_sha256s": false},
+		},
+		{
+			name: "v1 without tests",
+			manifest: `task_full_name = "Kvadrāti"
+`,
+			wantVersion: 1,
+			wantKeys:    map[string]bool{"test_sha256s": false, "task_full_name": true},
+		},
+		{
+			name: "v2 is left as it is",
+			manifest: `schema_version = 2
+task_full_name = "Kvadrāti"
+[[test_sha256s]]
+test_id = 1
+input_sha256 = "a"
+answer_sha256 = "b"
+`,
+			wantVersion: 2,
+			wantKeys:    map[string]bool{"test_sha256s": true},
+		},
+		{
+			name: "unknown keys are kept",
+			manifest: `task_full_name = "Kvadrāti"
+legacy_note = "keep me"
+`,
+			wantVersion: 1,
+			wantKeys:    map[string]bool{"legacy_note": true},
+		},
+		{
+			name:     "future version",
+			manifest: "schema_version = 3\n",
+			wantErr:  "unsupported manifest schema version 3",
+		},
+		{
+			name:     "invalid toml",
+			manifest: "task_full_name = ",
+			wantErr:  "failed to unmarshal manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrated, version, err := migrateManifest(tt.manifest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("got version %d, want %d", version, tt.wantVersion)
+			}
+
+			raw := map[string]interface{}{}
+			err = toml.Unmarshal(migrated, &raw)
+			if err != nil {
+				t.Fatalf("migrated manifest is not valid toml: %v", err)
+			}
+			if raw["schema_version"] != int64(CurrentManifestSchemaVersion) {
+				t.Errorf("got schema_version %v, want %d",
+					raw["schema_version"], CurrentManifestSchemaVersion)
+			}
+			for key, present := range tt.wantKeys {
+				if _, ok := raw[key]; ok != present {
+					t.Errorf("key %q present: %v, want %v", key, ok, present)
+				}
+			}
+		})
+	}
+}
+
+func TestParseManifestV1Tests(t *testing.T) {
+	manifest, version, err := parseManifest(`task_full_name = "Kvadrāti"
+[[tests_sha256s]]
+test_id = 1
+input_sha256 = "in"
+answer_sha256 = "ans"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != 1 {
+		t.Errorf("got version %d, want 1", version)
+	}
+	if len(manifest.TestSHA256s) != 1 || manifest.TestSHA256s[0].InputSHA256 != "in" {
+		t.Errorf("got tests %+v, want the migrated v1 test", manifest.TestSHA256s)
+	}
+}
+
+func TestParseManifestVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		wantVersion int
+		wantTests   int
+		wantErr     string
+	}{
+		{
+			name: "current version",
+			manifest: `schema_version = 2
+task_full_name = "Kvadrāti"
+[[test_sha256s]]
+test_id = 1
+input_sha256 = "in"
+answer_sha256 = "ans"
+`,
+			wantVersion: 2,
+			wantTests:   1,
+		},
+		{
+			name: "v1 is migrated",
+			manifest: `task_full_name = "Kvadrāti"
+[[tests_sha256s]]
+test_id = 1
+input_sha256 = "in"
+answer_sha256 = "ans"
+`,
+			wantVersion: 1,
+			wantTests:   1,
+		},
+		{
+			name:     "future version",
+			manifest: "schema_version = 3\n",
+			wantErr:  "unsupported manifest schema version 3",
+		},
+		{
+			name:     "invalid toml",
+			manifest: "task_full_name = ",
+			wantErr:  "failed to unmarshal manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, version, err := parseManifest(tt.manifest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("got version %d, want %d", version, tt.wantVersion)
+			}
+			if manifest.TaskFullName != "Kvadrāti" {
+				t.Errorf("got task name %q, want %q", manifest.TaskFullName, "Kvadrāti")
+			}
+			if len(manifest.TestSHA256s) != tt.wantTests {
+				t.Errorf("got %d tests, want %d", len(manifest.TestSHA256s), tt.wantTests)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package ddbtaskrepo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ManifestProblem struct {
+	Path    string // TOML path, e.g. "test_groups[3].test_ids"
+	Message string
+}
+
+func (p ManifestProblem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+var (
+	sha256Regexp  = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	imgUuidRegexp = regexp.MustCompile(
+		`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+)
+
+// ValidateManifest checks a TOML task manifest and reports all problems
+// at once. Manifests of older schema versions are migrated first.
+func ValidateManifest(manifest string) []ManifestProblem {
+	migrated, _, err := migrateManifest(manifest)
+	if err != nil {
+		return []ManifestProblem{{Message: err.Error()}}
+	}
+
+	problems := []ManifestProblem{}
+
+	tomlManifest := TaskTomlManifest{}
+	decoder := toml.NewDecoder(bytes.NewReader(migrated))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&tomlManifest)
+	var strictErr *toml.StrictMissingError
+	if errors.As(err, &strictErr) {
+		for _, missing := range strictErr.Errors {
+			problems = append(problems, ManifestProblem{
+				Path:    strings.Join(missing.Key(), "."),
+				Message: "unknown key",
+			})
+		}
+	} else if err != nil {
+		return []ManifestProblem{{Message: err.Error()}}
+	}
+
+	return append(problems, validateManifestFields(&tomlManifest)...)
+}
+
+func validateManifestFields(m *TaskTomlManifest) []ManifestProblem {
+	problems := []ManifestProblem{}
+	report := func(path string, format string, args ...interface{}) {
+		problems = append(problems, ManifestProblem{
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	switch domain.TaskState(m.State) {
+	case "", domain.TaskStateDraft, domain.TaskStateReview,
+		domain.TaskStatePublished, domain.TaskStateArchived:
+	default:
+		report("state", "unknown state %q", m.State)
+	}
+
+	if m.TaskFullName == "" {
+		report("task_full_name", "is required")
+	}
+	if m.Difficulty < 1 || m.Difficulty > 5 {
+		report("difficulty_1_to_5", "%d out of range", m.Difficulty)
+	}
+	if m.MemoryLimMB <= 0 {
+		report("memory_lim_megabytes", "%d must be positive", m.MemoryLimMB)
+	}
+	outputOnly := m.Evaluation != nil &&
+		m.Evaluation.Type == string(domain.EvaluationOutputOnly)
+	if m.CpuTimeInSecs < 0 || (m.CpuTimeInSecs == 0 && !outputOnly) {
+		report("cpu_time_in_seconds", "%g must be positive", m.CpuTimeInSecs)
+	}
+
+	if m.WallTimeInSecs != 0 && m.WallTimeInSecs < m.CpuTimeInSecs {
+		report("wall_time_in_seconds", "%g is less than cpu_time_in_seconds", m.WallTimeInSecs)
+	}
+	if m.WallTimeInSecs < 0 || m.WallTimeInSecs > 120 {
+		report("wall_time_in_seconds", "%g out of range", m.WallTimeInSecs)
+	}
+	if m.StackLimMB < 0 || (m.StackLimMB > 0 && m.StackLimMB > m.MemoryLimMB) {
+		report("stack_lim_megabytes", "%d out of range", m.StackLimMB)
+	}
+	limitLanguages := make([]string, 0, len(m.Limits))
+	for language := range m.Limits {
+		limitLanguages = append(limitLanguages, language)
+	}
+	sort.Strings(limitLanguages)
+	for _, language := range limitLanguages {
+		override := m.Limits[language]
+		path := fmt.Sprintf("limits.%s", language)
+		if override.CpuMultiplier < 0 || override.CpuMultiplier > 10 {
+			report(path+".cpu_multiplier", "%g out of range", override.CpuMultiplier)
+		}
+		if override.MemoryMultiplier < 0 || override.MemoryMultiplier > 10 {
+			report(path+".memory_multiplier", "%g out of range", override.MemoryMultiplier)
+		}
+	}
+
+	if m.Evaluation != nil {
+		problems = append(problems, validateEvaluation(m.Evaluation)...)
+	}
+
+	testIds := map[int]bool{}
+	for i, test := range m.TestSHA256s {
+		path := fmt.Sprintf("test_sha256s[%d]", i)
+		if test.TestID <= 0 {
+			report(path+".test_id", "%d must be positive", test.TestID)
+		} else if testIds[test.TestID] {
+			report(path+".test_id", "duplicate test %d", test.TestID)
+		}
+		testIds[test.TestID] = true
+		if !sha256Regexp.MatchString(test.InputSHA256) {
+			report(path+".input_sha256", "%q is not a sha256", test.InputSHA256)
+		}
+		if !sha256Regexp.MatchString(test.AnswerSHA256) {
+			report(path+".answer_sha256", "%q is not a sha256", test.AnswerSHA256)
+		}
+	}
+
+	groupIds := map[int]bool{}
+	for i, group := range m.TestGroups {
+		path := fmt.Sprintf("test_groups[%d]", i)
+		if groupIds[group.GroupID] {
+			report(path+".group_id", "duplicate group %d", group.GroupID)
+		}
+		groupIds[group.GroupID] = true
+		if group.Points < 0 {
+			report(path+".points", "%d must not be negative", group.Points)
+		}
+		for _, testId := range group.TestIDs {
+			if !testIds[testId] {
+				report(path+".test_ids", "unknown test %d", testId)
+			}
+		}
+		switch domain.ScoringPolicy(group.Scoring) {
+		case "", domain.ScoringMin, domain.ScoringSum:
+		case domain.ScoringSumOfPercentages:
+			if m.Evaluation == nil || m.Evaluation.Checker == nil {
+				report(path+".scoring", "%q requires evaluation.checker", group.Scoring)
+			}
+		default:
+			report(path+".scoring", "unknown scoring policy %q", group.Scoring)
+		}
+	}
+
+	subtasks := map[int]bool{}
+	for _, group := range m.TestGroups {
+		subtasks[group.Subtask] = true
+	}
+	describedSubtasks := map[int]bool{}
+	for i, subtask := range m.Subtasks {
+		path := fmt.Sprintf("subtasks[%d].subtask", i)
+		if describedSubtasks[subtask.Subtask] {
+			report(path, "duplicate subtask %d", subtask.Subtask)
+		}
+		describedSubtasks[subtask.Subtask] = true
+		if !subtasks[subtask.Subtask] {
+			report(path, "subtask %d has no test groups", subtask.Subtask)
+		}
+	}
+	for i, example := range m.Examples {
+		path := fmt.Sprintf("examples[%d]", i)
+		if example.Subtask != nil && !subtasks[*example.Subtask] {
+			report(path+".subtask", "unknown subtask %d", *example.Subtask)
+		}
+		if example.TestID != nil && !testIds[*example.TestID] {
+			report(path+".test_id", "unknown test %d", *example.TestID)
+		}
+	}
+
+	for i, pdf := range m.PDFSHA256s {
+		path := fmt.Sprintf("pdf_statements_sha256s[%d]", i)
+		if pdf.Language == "" {
+			report(path+".language", "is required")
+		}
+		if !sha256Regexp.MatchString(pdf.SHA256) {
+			report(path+".sha256", "%q is not a sha256", pdf.SHA256)
+		}
+	}
+
+	visibleSubtasks := map[int]bool{}
+	for _, subtask := range m.VisibleInputSTs {
+		visibleSubtasks[subtask] = true
+	}
+	inputSubtasks := map[int]bool{}
+	for i, stInputs := range m.VisInpStInputs {
+		if !visibleSubtasks[stInputs.Subtask] {
+			report(fmt.Sprintf("vis_inp_subtask_inputs[%d].subtask", i),
+				"subtask %d is not listed in visible_input_subtasks", stInputs.Subtask)
+		}
+		inputSubtasks[stInputs.Subtask] = true
+		for j, ref := range stInputs.InputRefs {
+			path := fmt.Sprintf("vis_inp_subtask_inputs[%d].input_refs[%d]", i, j)
+			if !sha256Regexp.MatchString(ref.SHA256) {
+				report(path+".sha256", "%q is not a sha256", ref.SHA256)
+			}
+			if ref.SizeBytes < 0 {
+				report(path+".size_bytes", "%d must not be negative", ref.SizeBytes)
+			}
+		}
+	}
+	for i, subtask := range m.VisibleInputSTs {
+		if !inputSubtasks[subtask] {
+			report(fmt.Sprintf("visible_input_subtasks[%d]", i),
+				"subtask %d has no vis_inp_subtask_inputs", subtask)
+		}
+	}
+
+	for i, st := range m.MDStatements {
+		sections := map[string]*string{
+			"story": &st.Story, "input": &st.Input, "output": &st.Output,
+			"notes": st.Notes, "scoring": st.Scoring,
+		}
+		for _, name := range []string{"story", "input", "output", "notes", "scoring"} {
+			if sections[name] == nil {
+				continue
+			}
+			for _, imgUuid := range imgUuidRegexp.FindAllString(*sections[name], -1) {
+				if _, ok := m.ImgUuidToObjKey[imgUuid]; !ok {
+					report(fmt.Sprintf("md_statements[%d].%s", i, name),
+						"image %s is not in img_uuid_to_obj_key", imgUuid)
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+func validateEvaluation(e *Evaluation) []ManifestProblem {
+	problems := []ManifestProblem{}
+	report := func(path string, format string, args ...interface{}) {
+		problems = append(problems, ManifestProblem{
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	switch domain.EvaluationType(e.Type) {
+	case domain.EvaluationExact, domain.EvaluationToken, domain.EvaluationOutputOnly:
+	case domain.EvaluationFloat:
+		if e.FloatTolerance <= 0 {
+			report("evaluation.float_tolerance", "%g must be positive", e.FloatTolerance)
+		}
+	case domain.EvaluationChecker:
+		if e.Checker == nil {
+			report("evaluation.checker", "is required for type %q", e.Type)
+		}
+	case domain.EvaluationInteractive:
+		if e.Interactor == nil {
+			report("evaluation.interactor", "is required for type %q", e.Type)
+		}
+	default:
+		report("evaluation.type", "unknown evaluation type %q", e.Type)
+	}
+
+	validateRef := func(path string, ref *SourceRef) {
+		if ref == nil {
+			return
+		}
+		if ref.Language == "" {
+			report(path+".language", "is required")
+		}
+		if !sha256Regexp.MatchString(ref.SHA256) {
+			report(path+".sha256", "%q is not a sha256", ref.SHA256)
+		}
+	}
+	validateRef("evaluation.checker", e.Checker)
+	validateRef("evaluation.interactor", e.Interactor)
+
+	languages := make([]string, 0, len(e.Graders))
+	for language := range e.Graders {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		for i, grader := range e.Graders[language] {
+			validateRef(fmt.Sprintf("evaluation.graders.%s[%d]", language, i), &grader)
+		}
+	}
+
+	return problems
+}
+
+// validateManifestChange returns a domain error listing the problems of
+// the manifest that were not among the problems of the stored manifest.
+// Manifests stored before validation may have problems of their own,
+// e.g. difficulty 0; they do not block writes that leave those fields
+// alone, such as state changes and renames. taskctl lint reports them.
+func validateManifestChange(storedProblems []ManifestProblem, m *TaskTomlManifest) error {
+	stored := map[string]bool{}
+	for _, problem := range storedProblems {
+		stored[problem.String()] = true
+	}
+
+	introduced := []ManifestProblem{}
+	for _, problem := range validateManifestFields(m) {
+		if !stored[problem.String()] {
+			introduced = append(introduced, problem)
+		}
+	}
+	if len(introduced) == 0 {
+		return nil
+	}
+	return domain.ErrorInvalidManifest(manifestProblemStrings(introduced))
+}
+
+func manifestProblemStrings(problems []ManifestProblem) []string {
+	res := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		res = append(res, problem.String())
+	}
+	return res
+}
+
+type RawManifest struct {
+	TaskID   string
+	Manifest string
+}
+
+// ListRawManifests returns the stored manifests as they are, without
+// migrating or validating them.
+func (r *dynamoDbTaskRepo) ListRawManifests() ([]RawManifest, error) {
+	rows, err := r.scanRows()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]RawManifest, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, RawManifest{TaskID: row.PublishedID, Manifest: row.Manifest})
+	}
+	return res, nil
+}
@@ -0,0 +1,164 @@
+package ddbtaskrepo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const validManifest = `schema_version = 2
+task_full_name = "Kvadrāti"
+difficulty_1_to_5 = 2
+memory_lim_megabytes = 256
+cpu_time_in_seconds = 1.0
+
+[[test_sha256s]]
+test_id = 1
+input_sha256 = "1111111111111111111111111111111111111111111111111111111111111111"
+answer_sha256 = "2222222222222222222222222222222222222222222222222222222222222222"
+
+[[test_groups]]
+group_id = 1
+points = 10
+public = false
+subtask = 1
+test_ids = [1]
+`
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name:     "valid",
+			manifest: validManifest,
+			want:     []string{},
+		},
+		{
+			name: "valid v1 manifest",
+			manifest: strings.NewReplacer(
+				"schema_version = 2\n", "",
+				"[[test_sha256s]]", "[[tests_sha256s]]",
+			).Replace(validManifest),
+			want: []string{},
+		},
+		{
+			name:     "unknown key",
+			manifest: "legacy_note = \"x\"\n" + validManifest,
+			want:     []string{"legacy_note: unknown key"},
+		},
+		{
+			name: "out of range values",
+			manifest: strings.NewReplacer(
+				"difficulty_1_to_5 = 2", "difficulty_1_to_5 = 6",
+				"memory_lim_megabytes = 256", "memory_lim_megabytes = 0",
+				"cpu_time_in_seconds = 1.0", "cpu_time_in_seconds = 0.0",
+			).Replace(validManifest),
+			want: []string{
+				"difficulty_1_to_5: 6 out of range",
+				"memory_lim_megabytes: 0 must be positive",
+				"cpu_time_in_seconds: 0 must be positive",
+			},
+		},
+		{
+			name: "output-only task without cpu time",
+			manifest: strings.Replace(validManifest, "cpu_time_in_seconds = 1.0",
+				"cpu_time_in_seconds = 0.0\n[evaluation]\ntype = \"output_only\"", 1),
+			want: []string{},
+		},
+		{
+			name:     "unknown test in group",
+			manifest: strings.Replace(validManifest, "test_ids = [1]", "test_ids = [1, 2]", 1),
+			want:     []string{"test_groups[0].test_ids: unknown test 2"},
+		},
+		{
+			name: "bad test hash",
+			manifest: strings.Replace(validManifest,
+				"input_sha256 = \"1111111111111111111111111111111111111111111111111111111111111111\"",
+				"input_sha256 = \"abc\"", 1),
+			want: []string{`test_sha256s[0].input_sha256: "abc" is not a sha256`},
+		},
+		{
+			name:     "unknown state",
+			manifest: "state = \"hidden\"\n" + validManifest,
+			want:     []string{`state: unknown state "hidden"`},
+		},
+		{
+			name:     "future schema version",
+			manifest: strings.Replace(validManifest, "schema_version = 2", "schema_version = 9", 1),
+			want:     []string{"unsupported manifest schema version 9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := manifestProblemStrings(ValidateManifest(tt.manifest))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got problems %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateManifestChange(t *testing.T) {
+	parse := func(manifest string) *TaskTomlManifest {
+		tomlManifest, _, err := parseManifest(manifest)
+		if err != nil {
+			t.Fatalf("failed to parse manifest: %v", err)
+		}
+		return tomlManifest
+	}
+	legacyManifest := strings.Replace(validManifest, "difficulty_1_to_5 = 2", "difficulty_1_to_5 = 0", 1)
+
+	tests := []struct {
+		name    string
+		stored  string // empty for a task that is not stored yet
+		change  func(m *TaskTomlManifest)
+		wantErr string
+	}{
+		{
+			name:   "legacy problem left alone",
+			stored: legacyManifest,
+			change: func(m *TaskTomlManifest) { m.State = "archived" },
+		},
+		{
+			name:    "new problem on a legacy manifest",
+			stored:  legacyManifest,
+			change:  func(m *TaskTomlManifest) { m.MemoryLimMB = 0 },
+			wantErr: "memory_lim_megabytes: 0 must be positive",
+		},
+		{
+			name:    "problem of a new task",
+			change:  func(m *TaskTomlManifest) { m.Difficulty = 0 },
+			wantErr: "difficulty_1_to_5: 0 out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storedProblems := []ManifestProblem{}
+			manifest := parse(validManifest)
+			if tt.stored != "" {
+				manifest = parse(tt.stored)
+				storedProblems = validateManifestFields(manifest)
+			}
+			tt.change(manifest)
+
+			err := validateManifestChange(storedProblems, manifest)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+			if strings.Contains(err.Error(), "difficulty") && tt.stored != "" {
+				t.Errorf("stored problem was reported: %v", err)
+			}
+		})
+	}
+}
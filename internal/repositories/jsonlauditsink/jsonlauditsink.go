@@ -0,0 +1,139 @@
+package jsonlauditsink
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// jsonlAuditSink appends audit events to a file, one JSON object per line.
+type jsonlAuditSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+type eventLine struct {
+	EventID     string            `json:"event_id"`
+	Action      string            `json:"action"`
+	Actor       string            `json:"actor"`
+	Timestamp   time.Time         `json:"timestamp"`
+	TaskID      string            `json:"task_id"`
+	OldRevision int               `json:"old_revision"`
+	NewRevision int               `json:"new_revision"`
+	Changes     []fieldChangeLine `json:"changes"`
+	BlobKey     string            `json:"blob_key,omitempty"`
+}
+
+type fieldChangeLine struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+func NewJsonlAuditSink(path string) *jsonlAuditSink {
+	return &jsonlAuditSink{path: path}
+}
+
+// RecordEvent implements service.AuditSink.
+func (s *jsonlAuditSink) RecordEvent(event domain.AuditEvent) error {
+	changes := []fieldChangeLine{}
+	for _, change := range event.Changes {
+		changes = append(changes, fieldChangeLine{
+			Field: change.Field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+
+	line, err := json.Marshal(eventLine{
+		EventID:     event.EventId,
+		Action:      string(event.Action),
+		Actor:       event.Actor,
+		Timestamp:   event.Timestamp,
+		TaskID:      event.TaskId,
+		OldRevision: event.OldRevision,
+		NewRevision: event.NewRevision,
+		Changes:     changes,
+		BlobKey:     event.BlobKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write audit event: %v", err)
+	}
+
+	return nil
+}
+
+// ListTaskEvents implements service.AuditSink.
+func (s *jsonlAuditSink) ListTaskEvents(taskId string) ([]domain.AuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := []domain.AuditEvent{}
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return events, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024) // a line holds the diff of a whole task
+	for scanner.Scan() {
+		line := eventLine{}
+		err = json.Unmarshal(scanner.Bytes(), &line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit event: %v", err)
+		}
+		if line.TaskID != taskId {
+			continue
+		}
+
+		changes := []domain.FieldChange{}
+		for _, change := range line.Changes {
+			changes = append(changes, domain.FieldChange{
+				Field: change.Field,
+				Old:   change.Old,
+				New:   change.New,
+			})
+		}
+		events = append(events, domain.AuditEvent{
+			EventId:     line.EventID,
+			Action:      domain.AuditAction(line.Action),
+			Actor:       line.Actor,
+			Timestamp:   line.Timestamp,
+			TaskId:      line.TaskID,
+			OldRevision: line.OldRevision,
+			NewRevision: line.NewRevision,
+			Changes:     changes,
+			BlobKey:     line.BlobKey,
+		})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %v", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,75 @@
+package s3blobstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type s3BlobStore struct {
+	s3        *s3.Client
+	bucket    string
+	keyPrefix string
+}
+
+func NewS3BlobStore(s3Client *s3.Client, bucket string) *s3BlobStore {
+	return NewPrefixedS3BlobStore(s3Client, bucket, "")
+}
+
+// NewPrefixedS3BlobStore stores every object under keyPrefix in the bucket,
+// for buckets that are shared with objects of other services.
+func NewPrefixedS3BlobStore(s3Client *s3.Client, bucket string, keyPrefix string) *s3BlobStore {
+	return &s3BlobStore{
+		s3:        s3Client,
+		bucket:    bucket,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// PutObject implements service.BlobStore.
+func (s *s3BlobStore) PutObject(key string, content []byte, contentType string) error {
+	_, err := s.s3.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.keyPrefix + key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %v", key, err)
+	}
+	return nil
+}
+
+// GetObject implements service.BlobStore.
+func (s *s3BlobStore) GetObject(key string) ([]byte, error) {
+	response, err := s.s3.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.keyPrefix + key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %v", key, err)
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %v", key, err)
+	}
+	return content, nil
+}
+
+// DeleteObject implements service.BlobStore.
+func (s *s3BlobStore) DeleteObject(key string) error {
+	_, err := s.s3.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.keyPrefix + key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %v", key, err)
+	}
+	return nil
+}
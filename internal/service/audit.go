@@ -0,0 +1,147 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// AuditSink stores audit events, e.g. in a database table or a file.
+type AuditSink interface {
+	RecordEvent(event domain.AuditEvent) error
+	ListTaskEvents(taskId string) ([]domain.AuditEvent, error)
+}
+
+type AuditService struct {
+	sink     AuditSink
+	taskRepo TaskRepo
+}
+
+func NewAuditService(sink AuditSink, taskRepo TaskRepo) *AuditService {
+	return &AuditService{sink: sink, taskRepo: taskRepo}
+}
+
+// ListTaskEvents returns the audit events of a task in chronological
+// order. Events are stored under the id the task had at the time, so
+// the events of its former ids are included. Only admins may read the
+// audit log.
+func (x *AuditService) ListTaskEvents(actor *auth.Identity, taskId string) ([]domain.AuditEvent, error) {
+	if actor == nil {
+		return nil, domain.ErrorAuthenticationRequired()
+	}
+	if !actor.HasRole(auth.RoleAdmin) {
+		return nil, domain.ErrorForbidden()
+	}
+
+	ids := []string{taskId}
+	task, err := x.taskRepo.GetTask(taskId)
+	if err == nil {
+		ids = append([]string{task.GetId()}, task.GetAliasIds()...)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	events := []domain.AuditEvent{}
+	for _, id := range ids {
+		idEvents, err := x.sink.ListTaskEvents(id)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, idEvents...)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].EventId < events[j].EventId
+	})
+	return events, nil
+}
+
+// taskChange captures a task before it is modified so that the change
+// can be recorded once the modified task is saved.
+type taskChange struct {
+	audit       *AuditService
+	actor       *auth.Identity
+	taskId      string
+	oldRevision int
+	oldFields   map[string]string
+	fieldsErr   error
+}
+
+func (x *AuditService) beginTaskChange(actor *auth.Identity, task *domain.Task) *taskChange {
+	oldFields, err := domain.TaskFields(task)
+	return &taskChange{
+		audit:       x,
+		actor:       actor,
+		taskId:      task.GetId(),
+		oldRevision: task.GetRevision(),
+		oldFields:   oldFields,
+		fieldsErr:   err,
+	}
+}
+
+// changes returns the field-level diff of the change. If the fields
+// cannot be encoded the event is recorded without it.
+func (c *taskChange) changes(saved *domain.Task) []domain.FieldChange {
+	newFields, err := domain.TaskFields(saved)
+	if c.fieldsErr != nil {
+		err = c.fieldsErr
+	}
+	if err != nil {
+		log.Printf("failed to diff audited task %s: %v", c.taskId, err)
+		return []domain.FieldChange{}
+	}
+	return domain.DiffTaskFields(c.oldFields, newFields)
+}
+
+// record stores an audit event for the saved task. The change has
+// already been made, so a failure to record it is only logged.
+func (c *taskChange) record(action domain.AuditAction, saved *domain.Task, blobKey string) {
+	actor := "anonymous"
+	if c.actor != nil {
+		actor = c.actor.Subject
+	}
+
+	now := time.Now().UTC()
+	eventId, err := newAuditEventId(now)
+	if err != nil {
+		log.Printf("failed to record audit event of task %s: %v", c.taskId, err)
+		return
+	}
+
+	event := domain.AuditEvent{
+		EventId:     eventId,
+		Action:      action,
+		Actor:       actor,
+		Timestamp:   now,
+		TaskId:      c.taskId,
+		OldRevision: c.oldRevision,
+		NewRevision: c.oldRevision,
+		Changes:     c.changes(saved),
+		BlobKey:     blobKey,
+	}
+	if saved != nil {
+		event.NewRevision = saved.GetRevision()
+	}
+
+	err = c.audit.sink.RecordEvent(event)
+	if err != nil {
+		log.Printf("failed to record audit event of task %s: %v", c.taskId, err)
+	}
+}
+
+// newAuditEventId returns an id that starts with the timestamp,
+// so ids sort chronologically.
+func newAuditEventId(timestamp time.Time) (string, error) {
+	b := make([]byte, 4)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate audit event id: %w", err)
+	}
+	return fmt.Sprintf("%s-%s", timestamp.Format("20060102T150405.000000000Z"),
+		hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// authorizeTaskEdit allows admins to modify any task
+// and authors only the tasks they own.
+func authorizeTaskEdit(actor *auth.Identity, task *domain.Task) error {
+	if actor == nil {
+		return domain.ErrorAuthenticationRequired()
+	}
+	if actor.HasRole(auth.RoleAdmin) {
+		return nil
+	}
+	if actor.HasRole(auth.RoleAuthor) && task.IsOwnedBy(actor.Subject) {
+		return nil
+	}
+	return domain.ErrorForbidden()
+}
+
+// authorizeCollectionEdit allows admins to modify any collection
+// and everyone else only the collections they own.
+func authorizeCollectionEdit(actor *auth.Identity, collection *domain.Collection) error {
+	if actor == nil {
+		return domain.ErrorAuthenticationRequired()
+	}
+	if actor.HasRole(auth.RoleAdmin) || collection.GetOwner() == actor.Subject {
+		return nil
+	}
+	return domain.ErrorForbidden()
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestAuthorizeTaskEdit(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	owner := &auth.Identity{Subject: "owner", Roles: []auth.Role{auth.RoleAuthor}}
+	author := &auth.Identity{Subject: "author", Roles: []auth.Role{auth.RoleAuthor}}
+	ownerReviewer := &auth.Identity{Subject: "owner", Roles: []auth.Role{auth.RoleReviewer}}
+
+	tests := []struct {
+		name       string
+		actor      *auth.Identity
+		owners     []string
+		wantStatus int // 0 if allowed
+	}{
+		{name: "anonymous", actor: nil, owners: []string{"owner"},
+			wantStatus: domain.UnauthorizedErrorCode},
+		{name: "admin", actor: admin, owners: []string{"owner"}},
+		{name: "admin on a task without owners", actor: admin, owners: []string{}},
+		{name: "owner", actor: owner, owners: []string{"other", "owner"}},
+		{name: "author who is not an owner", actor: author, owners: []string{"owner"},
+			wantStatus: domain.ForbiddenErrorCode},
+		{name: "author on a task without owners", actor: author, owners: []string{},
+			wantStatus: domain.ForbiddenErrorCode},
+		{name: "owner without the author role", actor: ownerReviewer, owners: []string{"owner"},
+			wantStatus: domain.ForbiddenErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := domain.NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			task.SetOwnerIds(tt.owners)
+
+			err = authorizeTaskEdit(tt.actor, task)
+			assertDomainErrorStatus(t, err, tt.wantStatus)
+		})
+	}
+}
+
+func TestSetTaskOwners(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	author := &auth.Identity{Subject: "author", Roles: []auth.Role{auth.RoleAuthor}}
+
+	tests := []struct {
+		name       string
+		actor      *auth.Identity
+		wantStatus int
+	}{
+		{name: "admin", actor: admin},
+		{name: "author", actor: author, wantStatus: domain.ForbiddenErrorCode},
+		{name: "anonymous", actor: nil, wantStatus: domain.UnauthorizedErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := domain.NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			repo := newMemTaskRepo(task)
+			srv := NewTaskService(repo, nil, NewAuditService(&memAuditSink{}, repo))
+
+			_, err = srv.SetTaskOwners(tt.actor, "kvadrati", []string{"author"})
+			assertDomainErrorStatus(t, err, tt.wantStatus)
+			if tt.wantStatus != 0 {
+				return
+			}
+
+			saved, err := repo.GetTask("kvadrati")
+			if err != nil {
+				t.Fatalf("failed to get task: %v", err)
+			}
+			err = authorizeTaskEdit(author, saved)
+			if err != nil {
+				t.Errorf("assigned owner cannot edit the task: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type CollectionRepo interface {
+	GetCollection(id string) (*domain.Collection, error)
+	ListCollections() ([]domain.Collection, error)
+	SaveCollection(collection *domain.Collection) error
+	DeleteCollection(id string) error
+}
+
+type CollectionService struct {
+	repo     CollectionRepo
+	taskRepo TaskRepo
+}
+
+func NewCollectionService(repo CollectionRepo, taskRepo TaskRepo) *CollectionService {
+	return &CollectionService{repo: repo, taskRepo: taskRepo}
+}
+
+type CollectionParams struct {
+	Title       string
+	Description string
+	TaskIds     []string
+}
+
+// CollectionView is a collection together with the tasks it references.
+// Task ids that no longer exist or are not publicly visible are skipped
+// and reported in MissingTaskIds.
+type CollectionView struct {
+	Collection     *domain.Collection
+	Tasks          []domain.Task
+	MissingTaskIds []string
+}
+
+func (x *CollectionService) ListCollections() ([]domain.Collection, error) {
+	return x.repo.ListCollections()
+}
+
+func (x *CollectionService) GetCollection(id string) (*CollectionView, error) {
+	collection, err := x.repo.GetCollection(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := x.taskRepo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	tasksById := make(map[string]domain.Task, len(tasks))
+	for _, task := range publicTasks(tasks) {
+		tasksById[task.GetId()] = task
+		for _, aliasId := range task.GetAliasIds() {
+			tasksById[aliasId] = task
+		}
+	}
+
+	view := &CollectionView{
+		Collection:     collection,
+		Tasks:          []domain.Task{},
+		MissingTaskIds: []string{},
+	}
+	for _, taskId := range collection.GetTaskIds() {
+		task, ok := tasksById[taskId]
+		if !ok {
+			view.MissingTaskIds = append(view.MissingTaskIds, taskId)
+			continue
+		}
+		view.Tasks = append(view.Tasks, task)
+	}
+
+	return view, nil
+}
+
+// CreateCollection creates a collection owned by the actor.
+func (x *CollectionService) CreateCollection(actor *auth.Identity, params CollectionParams) (*domain.Collection, error) {
+	if actor == nil {
+		return nil, domain.ErrorAuthenticationRequired()
+	}
+
+	id, err := newCollectionId()
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := domain.NewCollection(id, params.Title, actor.Subject)
+	if err != nil {
+		return nil, err
+	}
+	err = applyCollectionParams(collection, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = x.repo.SaveCollection(collection)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (x *CollectionService) UpdateCollection(actor *auth.Identity, id string, params CollectionParams) (*domain.Collection, error) {
+	collection, err := x.repo.GetCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	err = authorizeCollectionEdit(actor, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	err = collection.SetTitle(params.Title)
+	if err != nil {
+		return nil, err
+	}
+	err = applyCollectionParams(collection, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = x.repo.SaveCollection(collection)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (x *CollectionService) DeleteCollection(actor *auth.Identity, id string) error {
+	collection, err := x.repo.GetCollection(id)
+	if err != nil {
+		return err
+	}
+	err = authorizeCollectionEdit(actor, collection)
+	if err != nil {
+		return err
+	}
+	return x.repo.DeleteCollection(id)
+}
+
+func applyCollectionParams(collection *domain.Collection, params CollectionParams) error {
+	collection.SetDescription(params.Description)
+	taskIds := params.TaskIds
+	if taskIds == nil {
+		taskIds = []string{}
+	}
+	return collection.SetTaskIds(taskIds)
+}
+
+func newCollectionId() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate collection id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"math"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// DifficultyEstimator proposes the difficulty of a task from its
+// submission statistics. Tasks with fewer than MinAttempts attempts
+// get no proposal, as a handful of users says little about a task.
+type DifficultyEstimator struct {
+	MinAttempts int
+}
+
+func NewDifficultyEstimator() *DifficultyEstimator {
+	return &DifficultyEstimator{MinAttempts: 20}
+}
+
+// Estimate maps how easy the task turned out to be, the mean of its
+// solve rate and average best score, linearly onto the difficulty
+// scale: an ease of 0.8 or more is difficulty 1, below 0.2 it is 5.
+func (e *DifficultyEstimator) Estimate(stats *domain.TaskStatistics) (int, bool) {
+	if stats == nil || stats.GetAttempts() < e.MinAttempts {
+		return 0, false
+	}
+
+	ease := (stats.GetSolveRate() + stats.GetAvgBestScore()/100) / 2
+	difficulty := 5 - int(math.Floor(ease*5))
+	return max(1, min(5, difficulty)), true
+}
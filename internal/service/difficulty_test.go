@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestDifficultyEstimate(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     int
+		solves       int
+		avgBestScore float64
+		want         int
+		wantOk       bool
+	}{
+		{name: "too few attempts", attempts: 19, solves: 19, avgBestScore: 100, wantOk: false},
+		{name: "everyone solves it", attempts: 100, solves: 100, avgBestScore: 100, want: 1, wantOk: true},
+		{name: "easy", attempts: 100, solves: 80, avgBestScore: 80, want: 1, wantOk: true},
+		{name: "medium", attempts: 100, solves: 50, avgBestScore: 50, want: 3, wantOk: true},
+		{name: "hard", attempts: 100, solves: 20, avgBestScore: 40, want: 4, wantOk: true},
+		{name: "very hard", attempts: 100, solves: 5, avgBestScore: 15, want: 5, wantOk: true},
+		{name: "nobody scores", attempts: 20, solves: 0, avgBestScore: 0, want: 5, wantOk: true},
+	}
+
+	estimator := NewDifficultyEstimator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := domain.NewTaskStatistics("kvadrati", tt.attempts, tt.solves,
+				tt.avgBestScore, time.Now())
+			if err != nil {
+				t.Fatalf("failed to create statistics: %v", err)
+			}
+			got, ok := estimator.Estimate(stats)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("got (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDifficultyEstimateWithoutStatistics(t *testing.T) {
+	_, ok := NewDifficultyEstimator().Estimate(nil)
+	if ok {
+		t.Errorf("got an estimate without statistics")
+	}
+}
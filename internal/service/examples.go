@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ExampleMismatch struct {
+	TaskId  string
+	TestId  int64
+	Field   string // "input" or "output"
+	Message string
+}
+
+func (m ExampleMismatch) String() string {
+	if m.Field == "" {
+		return fmt.Sprintf("example linked to test %d: %s", m.TestId, m.Message)
+	}
+	return fmt.Sprintf("example linked to test %d: %s %s", m.TestId, m.Field, m.Message)
+}
+
+type ExampleService struct {
+	taskRepo  TaskRepo
+	testFiles BlobStore // test inputs and answers by their sha256
+}
+
+func NewExampleService(taskRepo TaskRepo, testFiles BlobStore) *ExampleService {
+	return &ExampleService{taskRepo: taskRepo, testFiles: testFiles}
+}
+
+// VerifyExamples checks that examples linked to a test repeat the
+// test's input and answer. Trailing whitespace is ignored.
+func (x *ExampleService) VerifyExamples(taskId string) ([]ExampleMismatch, error) {
+	task, err := x.taskRepo.GetTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	return x.verifyTaskExamples(task)
+}
+
+func (x *ExampleService) verifyTaskExamples(task *domain.Task) ([]ExampleMismatch, error) {
+	testsById := map[int64]domain.TestSha256Ref{}
+	for _, test := range task.GetTests() {
+		testsById[test.TestId] = test
+	}
+
+	mismatches := []ExampleMismatch{}
+	for _, example := range task.GetExamples() {
+		if example.TestId == nil {
+			continue
+		}
+		test, ok := testsById[*example.TestId]
+		if !ok {
+			mismatches = append(mismatches, ExampleMismatch{
+				TaskId: task.GetId(), TestId: *example.TestId, Message: "test does not exist",
+			})
+			continue
+		}
+
+		for _, file := range []struct {
+			field   string
+			sha256  string
+			example string
+		}{
+			{"input", test.InputSha256, example.Input},
+			{"output", test.AnswerSha256, example.Output},
+		} {
+			content, err := x.testFiles.GetObject(file.sha256)
+			if err != nil {
+				return nil, err
+			}
+			if normalizeTestText(string(content)) != normalizeTestText(file.example) {
+				mismatches = append(mismatches, ExampleMismatch{
+					TaskId:  task.GetId(),
+					TestId:  test.TestId,
+					Field:   file.field,
+					Message: "does not match the test file",
+				})
+			}
+		}
+	}
+
+	return mismatches, nil
+}
+
+// checkExampleChange returns a domain error listing the example
+// mismatches of the task that the stored task does not have, so that
+// tasks stored before the check can still be saved.
+func (x *ExampleService) checkExampleChange(task *domain.Task) error {
+	if !hasLinkedExamples(task) {
+		return nil
+	}
+
+	stored, err := x.taskRepo.GetTask(task.GetId())
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if stored != nil && reflect.DeepEqual(stored.GetExamples(), task.GetExamples()) &&
+		reflect.DeepEqual(stored.GetTests(), task.GetTests()) {
+		return nil
+	}
+
+	mismatches, err := x.verifyTaskExamples(task)
+	if err != nil || len(mismatches) == 0 {
+		return err
+	}
+	storedMismatches := map[string]bool{}
+	if stored != nil {
+		previous, err := x.verifyTaskExamples(stored)
+		if err != nil {
+			return err
+		}
+		for _, mismatch := range previous {
+			storedMismatches[mismatch.String()] = true
+		}
+	}
+
+	problems := []string{}
+	for _, mismatch := range mismatches {
+		if !storedMismatches[mismatch.String()] {
+			problems = append(problems, mismatch.String())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return domain.ErrorInvalidManifest(problems)
+}
+
+func hasLinkedExamples(task *domain.Task) bool {
+	for _, example := range task.GetExamples() {
+		if example.TestId != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleCheckingTaskRepo rejects saves that introduce examples whose
+// text does not match their linked test.
+type exampleCheckingTaskRepo struct {
+	TaskRepo
+	examples *ExampleService
+}
+
+// NewExampleCheckingTaskRepo wraps repo so that saving a task checks
+// its examples against the test files like taskctl lint --check-examples.
+func NewExampleCheckingTaskRepo(repo TaskRepo, testFiles BlobStore) TaskRepo {
+	return &exampleCheckingTaskRepo{
+		TaskRepo: repo,
+		examples: NewExampleService(repo, testFiles),
+	}
+}
+
+func (r *exampleCheckingTaskRepo) SaveTask(task *domain.Task) error {
+	err := r.examples.checkExampleChange(task)
+	if err != nil {
+		return err
+	}
+	return r.TaskRepo.SaveTask(task)
+}
+
+// SaveTasks checks every task like SaveTask and saves the others.
+func (r *exampleCheckingTaskRepo) SaveTasks(tasks []*domain.Task) []error {
+	errs := make([]error, len(tasks))
+	checked := []*domain.Task{}
+	indexes := []int{}
+	for i, task := range tasks {
+		errs[i] = r.examples.checkExampleChange(task)
+		if errs[i] == nil {
+			checked = append(checked, task)
+			indexes = append(indexes, i)
+		}
+	}
+	for i, err := range r.TaskRepo.SaveTasks(checked) {
+		errs[indexes[i]] = err
+	}
+	return errs
+}
+
+func normalizeTestText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func isNotFound(err error) bool {
+	var domainErr *domain.DomainError
+	return errors.As(err, &domainErr) && domainErr.StatusCode == domain.NotFoundErrorCode
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func newExampleTestTask(t *testing.T, examples ...domain.Example) *domain.Task {
+	t.Helper()
+	task, err := domain.NewTask("kvadrati", "Kvadrāti")
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	err = task.SetTests([]domain.TestSha256Ref{
+		{TestId: 1, InputSha256: "in1", AnswerSha256: "ans1"},
+	})
+	if err != nil {
+		t.Fatalf("failed to set tests: %v", err)
+	}
+	for _, example := range examples {
+		task.AddExample(example)
+	}
+	return task
+}
+
+func newExampleTestFiles() *memBlobStore {
+	return &memBlobStore{objects: map[string][]byte{
+		"in1":  []byte("3 4\n"),
+		"ans1": []byte("7  \r\n"),
+	}}
+}
+
+func linkedExample(testId int64, input string, output string) domain.Example {
+	return domain.Example{Input: input, Output: output, TestId: &testId}
+}
+
+func TestVerifyExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		example domain.Example
+		want    []string
+	}{
+		{
+			name:    "matching up to trailing whitespace",
+			example: linkedExample(1, "3 4", "7"),
+			want:    []string{},
+		},
+		{
+			name:    "unlinked example is not checked",
+			example: domain.Example{Input: "1 1", Output: "2"},
+			want:    []string{},
+		},
+		{
+			name:    "mismatched output",
+			example: linkedExample(1, "3 4", "8"),
+			want:    []string{"example linked to test 1: output does not match the test file"},
+		},
+		{
+			name:    "missing test",
+			example: linkedExample(2, "3 4", "7"),
+			want:    []string{"example linked to test 2: test does not exist"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemTaskRepo(newExampleTestTask(t, tt.example))
+			srv := NewExampleService(repo, newExampleTestFiles())
+
+			mismatches, err := srv.VerifyExamples("kvadrati")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := []string{}
+			for _, mismatch := range mismatches {
+				got = append(got, mismatch.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExampleCheckingTaskRepo(t *testing.T) {
+	matching := linkedExample(1, "3 4", "7")
+	mismatched := linkedExample(1, "3 4", "8")
+
+	tests := []struct {
+		name       string
+		stored     *domain.Example // nil if the task is new
+		saved      domain.Example
+		wantStatus int // 0 if the save succeeds
+	}{
+		{name: "new task with matching example", saved: matching},
+		{name: "new task with mismatched example", saved: mismatched,
+			wantStatus: domain.UnprocessableEntityErrorCode},
+		{name: "example changed to mismatch", stored: &matching, saved: mismatched,
+			wantStatus: domain.UnprocessableEntityErrorCode},
+		{name: "stored mismatch left alone", stored: &mismatched, saved: mismatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newMemTaskRepo()
+			if tt.stored != nil {
+				err := inner.SaveTask(newExampleTestTask(t, *tt.stored))
+				if err != nil {
+					t.Fatalf("failed to store task: %v", err)
+				}
+			}
+			repo := NewExampleCheckingTaskRepo(inner, newExampleTestFiles())
+
+			task := newExampleTestTask(t, tt.saved)
+			if stored, err := inner.GetTask("kvadrati"); err == nil {
+				task.SetRevision(stored.GetRevision())
+			}
+			err := repo.SaveTask(task)
+			if tt.wantStatus != 0 {
+				assertDomainErrorStatus(t, err, tt.wantStatus)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := inner.GetTask("kvadrati"); err != nil {
+				t.Errorf("task was not saved: %v", err)
+			}
+		})
+	}
+}
+
+func TestExampleCheckingTaskRepoSaveTasks(t *testing.T) {
+	inner := newMemTaskRepo()
+	repo := NewExampleCheckingTaskRepo(inner, newExampleTestFiles())
+
+	mismatched := newExampleTestTask(t, linkedExample(1, "3 4", "8"))
+	matching, err := domain.NewTask("trijsturi", "Trijstūri")
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	errs := repo.SaveTasks([]*domain.Task{mismatched, matching})
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2", len(errs))
+	}
+	assertDomainErrorStatus(t, errs[0], domain.UnprocessableEntityErrorCode)
+	if errs[1] != nil {
+		t.Errorf("unexpected error: %v", errs[1])
+	}
+	if _, err := inner.GetTask("kvadrati"); err == nil {
+		t.Errorf("task with mismatched example was saved")
+	}
+	if _, err := inner.GetTask("trijsturi"); err != nil {
+		t.Errorf("other task was not saved: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type OlympiadRepo interface {
+	GetOlympiad(id string) (*domain.Olympiad, error)
+	ListOlympiads() ([]domain.Olympiad, error)
+	SaveOlympiad(olympiad *domain.Olympiad) error
+}
+
+type OlympiadService struct {
+	repo     OlympiadRepo
+	taskRepo TaskRepo
+	audit    *AuditService
+}
+
+func NewOlympiadService(repo OlympiadRepo, taskRepo TaskRepo, audit *AuditService) *OlympiadService {
+	return &OlympiadService{repo: repo, taskRepo: taskRepo, audit: audit}
+}
+
+func (x *OlympiadService) GetOlympiad(id string) (*domain.Olympiad, error) {
+	return x.repo.GetOlympiad(id)
+}
+
+func (x *OlympiadService) ListOlympiads() ([]domain.Olympiad, error) {
+	return x.repo.ListOlympiads()
+}
+
+// ListOlympiadTasks returns the tasks that reference the olympiad by id.
+func (x *OlympiadService) ListOlympiadTasks(olympiadId string) ([]domain.Task, error) {
+	_, err := x.repo.GetOlympiad(olympiadId)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := x.taskRepo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Task{}
+	for _, task := range publicTasks(tasks) {
+		if task.GetOriginOlympiadId() == olympiadId {
+			res = append(res, task)
+		}
+	}
+	return res, nil
+}
+
+type OlympiadMigration struct {
+	TaskId         string
+	OriginOlympiad string
+	OlympiadId     string // empty if the name could not be mapped
+}
+
+// MigrateOriginOlympiads maps the free-form origin olympiad strings
+// of all tasks onto catalogue entries, creating missing entries.
+// When dryRun is set nothing is written.
+func (x *OlympiadService) MigrateOriginOlympiads(actor *auth.Identity, dryRun bool) ([]OlympiadMigration, error) {
+	if !actor.HasRole(auth.RoleAdmin) {
+		return nil, domain.ErrorForbidden()
+	}
+
+	tasks, err := x.taskRepo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := x.repo.ListOlympiads()
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, olympiad := range existing {
+		known[olympiad.GetId()] = true
+	}
+
+	migrations := []OlympiadMigration{}
+	for _, task := range tasks {
+		origin := task.GetOriginOlympiad()
+		if origin == "" || task.GetOriginOlympiadId() != "" {
+			continue
+		}
+
+		id, year, stage, ok := domain.ParseOlympiadName(origin)
+		if !ok {
+			migrations = append(migrations, OlympiadMigration{
+				TaskId:         task.GetId(),
+				OriginOlympiad: origin,
+			})
+			continue
+		}
+		migrations = append(migrations, OlympiadMigration{
+			TaskId:         task.GetId(),
+			OriginOlympiad: origin,
+			OlympiadId:     id,
+		})
+		if dryRun {
+			continue
+		}
+
+		if !known[id] {
+			olympiad, err := domain.NewOlympiad(id, year)
+			if err != nil {
+				return nil, err
+			}
+			acronym := strings.ToUpper(strings.SplitN(id, "-", 2)[0])
+			olympiad.SetName("lv", strings.TrimSpace(fmt.Sprintf("%s %d %s", acronym, year, stage)))
+			olympiad.SetStage(stage)
+			err = x.repo.SaveOlympiad(olympiad)
+			if err != nil {
+				return nil, fmt.Errorf("failed to save olympiad %s: %w", id, err)
+			}
+			known[id] = true
+		}
+
+		task := task
+		change := x.audit.beginTaskChange(actor, &task)
+		task.SetOriginOlympiadId(id)
+		err = x.taskRepo.SaveTask(&task)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save task %s: %w", task.GetId(), err)
+		}
+		change.record(domain.AuditActionUpdate, &task, "")
+	}
+
+	return migrations, nil
+}
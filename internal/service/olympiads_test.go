@@ -0,0 +1,102 @@
+package service
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// memOlympiadRepo is an in-memory OlympiadRepo.
+type memOlympiadRepo struct {
+	olympiads map[string]domain.Olympiad
+}
+
+func (r *memOlympiadRepo) GetOlympiad(id string) (*domain.Olympiad, error) {
+	olympiad, ok := r.olympiads[id]
+	if !ok {
+		return nil, domain.ErrorOlympiadNotFound(id)
+	}
+	return &olympiad, nil
+}
+
+func (r *memOlympiadRepo) ListOlympiads() ([]domain.Olympiad, error) {
+	olympiads := []domain.Olympiad{}
+	for _, olympiad := range r.olympiads {
+		olympiads = append(olympiads, olympiad)
+	}
+	return olympiads, nil
+}
+
+func (r *memOlympiadRepo) SaveOlympiad(olympiad *domain.Olympiad) error {
+	r.olympiads[olympiad.GetId()] = *olympiad
+	return nil
+}
+
+func TestMigrateOriginOlympiads(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	origins := map[string]string{
+		"kvadrati":  "LIO 2023",
+		"trijsturi": "LIO 2023 valsts",
+		"apli":      "LIO2023",
+		"skaitli":   "Ziemassvētku sacensības",
+	}
+	wantIds := map[string]string{
+		"kvadrati":  "lio-2023",
+		"trijsturi": "lio-2023-valsts",
+		"apli":      "lio-2023",
+		"skaitli":   "",
+	}
+
+	tasks := []*domain.Task{}
+	for id, origin := range origins {
+		task, err := domain.NewTask(id, id)
+		if err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		task.SetOriginOlympiad(origin)
+		tasks = append(tasks, task)
+	}
+	repo := newMemTaskRepo(tasks...)
+	olympiads := &memOlympiadRepo{olympiads: map[string]domain.Olympiad{}}
+	srv := NewOlympiadService(olympiads, repo, NewAuditService(&memAuditSink{}, repo))
+
+	migrations, err := srv.MigrateOriginOlympiads(admin, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(migrations) != len(origins) {
+		t.Fatalf("got %d migrations, want %d", len(migrations), len(origins))
+	}
+	for _, migration := range migrations {
+		if migration.OlympiadId != wantIds[migration.TaskId] {
+			t.Errorf("task %s: got olympiad %q, want %q", migration.TaskId,
+				migration.OlympiadId, wantIds[migration.TaskId])
+		}
+		task, err := repo.GetTask(migration.TaskId)
+		if err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		if task.GetOriginOlympiadId() != wantIds[migration.TaskId] {
+			t.Errorf("task %s: stored olympiad %q, want %q", migration.TaskId,
+				task.GetOriginOlympiadId(), wantIds[migration.TaskId])
+		}
+	}
+
+	ids := []string{}
+	for id := range olympiads.olympiads {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) != 2 || ids[0] != "lio-2023" || ids[1] != "lio-2023-valsts" {
+		t.Fatalf("got olympiads %v, want lio-2023 and lio-2023-valsts", ids)
+	}
+	stage := olympiads.olympiads["lio-2023-valsts"]
+	if stage.GetStage() != "valsts" || stage.GetName("lv") != "LIO 2023 valsts" {
+		t.Errorf("got stage %q named %q", stage.GetStage(), stage.GetName("lv"))
+	}
+
+	_, err = srv.ListOlympiadTasks("lio-1999")
+	assertDomainErrorStatus(t, err, domain.NotFoundErrorCode)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/rendering"
+)
+
+type BlobStore interface {
+	GetObject(key string) ([]byte, error)
+	PutObject(key string, content []byte, contentType string) error
+	DeleteObject(key string) error
+}
+
+// PdfStatementObjKey is the public bucket object key of a pdf statement.
+func PdfStatementObjKey(sha256 string) string {
+	return fmt.Sprintf("task-pdf-statements/%s.pdf", sha256)
+}
+
+type PdfStatementService struct {
+	taskRepo     TaskRepo
+	blobStore    BlobStore
+	htmlRenderer *rendering.HtmlStatementRenderer
+	katex        *rendering.KatexAssets
+	pdfRenderer  rendering.PdfRenderer
+	audit        *AuditService
+}
+
+func NewPdfStatementService(taskRepo TaskRepo, blobStore BlobStore,
+	htmlRenderer *rendering.HtmlStatementRenderer,
+	katex *rendering.KatexAssets,
+	pdfRenderer rendering.PdfRenderer,
+	audit *AuditService) *PdfStatementService {
+	return &PdfStatementService{
+		taskRepo:     taskRepo,
+		blobStore:    blobStore,
+		htmlRenderer: htmlRenderer,
+		katex:        katex,
+		pdfRenderer:  pdfRenderer,
+		audit:        audit,
+	}
+}
+
+// GeneratePdfStatement renders the markdown statement of the task in the
+// given language to a pdf, stores it as a blob and registers it on the task.
+// An existing pdf statement in that language is replaced only if replace is set.
+func (x *PdfStatementService) GeneratePdfStatement(actor *auth.Identity, taskId string,
+	language string, replace bool) (string, error) {
+	task, err := getLiveTask(x.taskRepo, taskId)
+	if err != nil {
+		return "", err
+	}
+	err = authorizeTaskEdit(actor, task)
+	if err != nil {
+		return "", err
+	}
+
+	statement, ok := task.GetMarkdownStatements()[language]
+	if !ok {
+		return "", domain.ErrorMarkdownStatementNotFound(language)
+	}
+	for _, pdf := range task.GetPdfStatementSha256s() {
+		if pdf.Language == language && !replace {
+			return "", domain.ErrorPdfStatementExists(language)
+		}
+	}
+
+	rendered, err := x.htmlRenderer.Render(task, language, statement)
+	if err != nil {
+		return "", err
+	}
+	if len(rendered.UnresolvedImgUuids) > 0 {
+		return "", fmt.Errorf("statement references unknown images: %v",
+			rendered.UnresolvedImgUuids)
+	}
+
+	htmlDoc, err := x.htmlRenderer.StatementHtmlDocument(task, language, rendered, x.katex)
+	if err != nil {
+		return "", err
+	}
+
+	pdf, err := x.pdfRenderer.RenderPdf(htmlDoc)
+	if err != nil {
+		return "", fmt.Errorf("failed to render pdf: %w", err)
+	}
+
+	hash := sha256.Sum256(pdf)
+	sha256Hex := hex.EncodeToString(hash[:])
+	objKey := PdfStatementObjKey(sha256Hex)
+	err = x.blobStore.PutObject(objKey, pdf, "application/pdf")
+	if err != nil {
+		return "", err
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	task.RemovePdfStatementSha256s(language)
+	task.AddPdfStatementSha256(language, sha256Hex)
+	err = x.taskRepo.SaveTask(task)
+	if err != nil {
+		return "", err
+	}
+	change.record(domain.AuditActionBlobUpload, task, objKey)
+	return sha256Hex, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/rendering"
+)
+
+// echoPdfRenderer returns its html input as the pdf, so that different
+// statements produce different pdfs.
+type echoPdfRenderer struct{}
+
+func (echoPdfRenderer) RenderPdf(htmlDoc []byte) ([]byte, error) {
+	return htmlDoc, nil
+}
+
+func TestGeneratePdfStatement(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+
+	tests := []struct {
+		name     string
+		existing bool
+		replace  bool
+		wantErr  bool
+	}{
+		{name: "new statement", existing: false, replace: false},
+		{name: "existing statement without replace", existing: true, replace: false, wantErr: true},
+		{name: "replaced statement", existing: true, replace: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := domain.NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			task.AddMarkdownStatement("lv", domain.MarkdownStatement{
+				Story: "Stāsts", Input: "Ievaddati", Output: "Izvaddati",
+			})
+			oldKey := PdfStatementObjKey("old")
+			if tt.existing {
+				task.AddPdfStatementSha256("lv", "old")
+			}
+
+			repo := newMemTaskRepo(task)
+			blobs := newMemBlobStore(oldKey)
+			htmlRenderer := rendering.NewHtmlStatementRenderer(
+				rendering.NewStatementRenderer(func(objKey string) string { return objKey }))
+			srv := NewPdfStatementService(repo, blobs, htmlRenderer, &rendering.KatexAssets{},
+				echoPdfRenderer{}, NewAuditService(&memAuditSink{}, repo))
+
+			sha256Hex, err := srv.GeneratePdfStatement(admin, "kvadrati", "lv", tt.replace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			// earlier revisions still reference a replaced pdf
+			if _, ok := blobs.objects[oldKey]; !ok {
+				t.Errorf("old pdf was deleted")
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if _, ok := blobs.objects[PdfStatementObjKey(sha256Hex)]; !ok {
+				t.Errorf("new pdf was not stored")
+			}
+			saved, err := repo.GetTask("kvadrati")
+			if err != nil {
+				t.Fatalf("failed to get task: %v", err)
+			}
+			want := []domain.PdfSha256Ref{{Language: "lv", Sha256: sha256Hex}}
+			if !reflect.DeepEqual(saved.GetPdfStatementSha256s(), want) {
+				t.Errorf("got pdf statements %v, want %v", saved.GetPdfStatementSha256s(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type PurgeService struct {
+	taskRepo    TaskRepo
+	testFiles   BlobStore
+	publicFiles BlobStore
+	audit       *AuditService
+}
+
+func NewPurgeService(taskRepo TaskRepo, testFiles BlobStore, publicFiles BlobStore,
+	audit *AuditService) *PurgeService {
+	return &PurgeService{
+		taskRepo:    taskRepo,
+		testFiles:   testFiles,
+		publicFiles: publicFiles,
+		audit:       audit,
+	}
+}
+
+type PurgeResult struct {
+	TaskIds        []string
+	TestFileKeys   []string // test bucket objects no remaining revision references
+	PublicFileKeys []string // public bucket objects no remaining revision references
+}
+
+// PurgeDeletedTasks permanently removes the tasks that were deleted
+// before the given time, together with the blobs that no revision of
+// a remaining task references. When dryRun is set nothing is removed.
+func (x *PurgeService) PurgeDeletedTasks(actor *auth.Identity, deletedBefore time.Time,
+	dryRun bool) (*PurgeResult, error) {
+	if !actor.HasRole(auth.RoleAdmin) {
+		return nil, domain.ErrorForbidden()
+	}
+
+	tasks, err := x.taskRepo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	// revisions are stored under the id the task had at the time
+	purged := []domain.Task{}
+	purgedIds := map[string]bool{}
+	for _, task := range tasks {
+		deletedAt := task.GetDeletedAt()
+		if deletedAt != nil && deletedAt.Before(deletedBefore) {
+			purged = append(purged, task)
+			purgedIds[task.GetId()] = true
+			for _, aliasId := range task.GetAliasIds() {
+				purgedIds[aliasId] = true
+			}
+		}
+	}
+
+	revisions, err := x.taskRepo.ListAllRevisions()
+	if err != nil {
+		return nil, err
+	}
+
+	purgedTestFiles, purgedPublicFiles := map[string]bool{}, map[string]bool{}
+	keptTestFiles, keptPublicFiles := map[string]bool{}, map[string]bool{}
+	for _, revision := range revisions {
+		testFiles, publicFiles := keptTestFiles, keptPublicFiles
+		if purgedIds[revision.GetId()] {
+			testFiles, publicFiles = purgedTestFiles, purgedPublicFiles
+		}
+		for _, key := range testFileKeys(&revision) {
+			testFiles[key] = true
+		}
+		for _, key := range publicFileKeys(&revision) {
+			publicFiles[key] = true
+		}
+	}
+
+	result := &PurgeResult{TaskIds: []string{}}
+	for _, task := range purged {
+		result.TaskIds = append(result.TaskIds, task.GetId())
+	}
+	unreferencedTestFiles, unreferencedPublicFiles := map[string]bool{}, map[string]bool{}
+	for key := range purgedTestFiles {
+		if !keptTestFiles[key] {
+			unreferencedTestFiles[key] = true
+		}
+	}
+	for key := range purgedPublicFiles {
+		if !keptPublicFiles[key] {
+			unreferencedPublicFiles[key] = true
+		}
+	}
+	result.TestFileKeys = sortedKeys(unreferencedTestFiles)
+	result.PublicFileKeys = sortedKeys(unreferencedPublicFiles)
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, task := range purged {
+		task := task
+		change := x.audit.beginTaskChange(actor, &task)
+		err = x.taskRepo.DeleteTask(task.GetId())
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge task %s: %w", task.GetId(), err)
+		}
+		change.record(domain.AuditActionDelete, nil, "")
+	}
+	for _, key := range result.TestFileKeys {
+		err = x.testFiles.DeleteObject(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range result.PublicFileKeys {
+		err = x.publicFiles.DeleteObject(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// testFileKeys returns the test files the task references. They are
+// stored under their sha256.
+func testFileKeys(task *domain.Task) []string {
+	keys := []string{}
+	for _, test := range task.GetTests() {
+		keys = append(keys, test.InputSha256, test.AnswerSha256)
+	}
+	for _, st := range task.GetVisInpStInputs() {
+		for _, input := range st.Inputs {
+			if !input.IsInline() {
+				keys = append(keys, input.Sha256)
+			}
+		}
+	}
+	return keys
+}
+
+// publicFileKeys returns the public bucket objects the task references.
+func publicFileKeys(task *domain.Task) []string {
+	keys := []string{}
+	for _, pdf := range task.GetPdfStatementSha256s() {
+		keys = append(keys, PdfStatementObjKey(pdf.Sha256))
+	}
+	for _, objKey := range task.GetImgUuidToObjKey() {
+		keys = append(keys, objKey)
+	}
+	if task.GetIllustrationImgObjKey() != "" {
+		keys = append(keys, task.GetIllustrationImgObjKey())
+	}
+	return keys
+}
+
+func sortedKeys(set map[string]bool) []string {
+	res := make([]string, 0, len(set))
+	for key := range set {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
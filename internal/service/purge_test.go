@@ -0,0 +1,142 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// newPurgeTestRepo stores three tasks:
+//   - "vecs", deleted 60 days ago, whose revisions reference the tests
+//     in1/ans1 and in2/ans1 and the pdfs p-old and p-new;
+//   - "jauns", live, whose first revision shares in1 and p-old;
+//   - "nesens", deleted a day ago, which shares in2.
+func newPurgeTestRepo(t *testing.T) *memTaskRepo {
+	t.Helper()
+	newTestTask := func(id string, tests ...domain.TestSha256Ref) *domain.Task {
+		task, err := domain.NewTask(id, id)
+		if err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		err = task.SetTests(tests)
+		if err != nil {
+			t.Fatalf("failed to set tests: %v", err)
+		}
+		return task
+	}
+	setTests := func(task *domain.Task, tests ...domain.TestSha256Ref) {
+		err := task.SetTests(tests)
+		if err != nil {
+			t.Fatalf("failed to set tests: %v", err)
+		}
+	}
+	save := func(repo *memTaskRepo, task *domain.Task) {
+		err := repo.SaveTask(task)
+		if err != nil {
+			t.Fatalf("failed to save task: %v", err)
+		}
+	}
+	deletedAt := func(days int) *time.Time {
+		at := time.Now().AddDate(0, 0, -days)
+		return &at
+	}
+
+	repo := newMemTaskRepo()
+
+	old := newTestTask("vecs", domain.TestSha256Ref{TestId: 1, InputSha256: "in1", AnswerSha256: "ans1"})
+	old.AddPdfStatementSha256("lv", "p-old")
+	save(repo, old)
+	setTests(old, domain.TestSha256Ref{TestId: 1, InputSha256: "in2", AnswerSha256: "ans1"})
+	old.AddPdfStatementSha256("en", "p-new")
+	old.SetDeletedAt(deletedAt(60))
+	save(repo, old)
+
+	live := newTestTask("jauns", domain.TestSha256Ref{TestId: 1, InputSha256: "in1", AnswerSha256: "ans-live"})
+	live.AddPdfStatementSha256("lv", "p-old")
+	save(repo, live)
+	setTests(live, domain.TestSha256Ref{TestId: 1, InputSha256: "in3", AnswerSha256: "ans-live"})
+	save(repo, live)
+
+	recent := newTestTask("nesens", domain.TestSha256Ref{TestId: 1, InputSha256: "in2", AnswerSha256: "ans-recent"})
+	recent.SetDeletedAt(deletedAt(1))
+	save(repo, recent)
+
+	return repo
+}
+
+func TestPurgeDeletedTasks(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	testFileKeys := []string{"in1", "in2", "in3", "ans1", "ans-live", "ans-recent"}
+	publicFileKeys := []string{PdfStatementObjKey("p-old"), PdfStatementObjKey("p-new")}
+	wantResult := &PurgeResult{
+		TaskIds:        []string{"vecs"},
+		TestFileKeys:   []string{"ans1"},
+		PublicFileKeys: []string{PdfStatementObjKey("p-new")},
+	}
+
+	tests := []struct {
+		name       string
+		actor      *auth.Identity
+		dryRun     bool
+		wantStatus int // 0 if the purge succeeds
+	}{
+		{name: "purge", actor: admin},
+		{name: "dry run", actor: admin, dryRun: true},
+		{name: "not an admin", actor: &auth.Identity{Subject: "autors",
+			Roles: []auth.Role{auth.RoleAuthor}}, wantStatus: domain.ForbiddenErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newPurgeTestRepo(t)
+			testFiles := newMemBlobStore(testFileKeys...)
+			publicFiles := newMemBlobStore(publicFileKeys...)
+			sink := &memAuditSink{}
+			srv := NewPurgeService(repo, testFiles, publicFiles, NewAuditService(sink, repo))
+
+			result, err := srv.PurgeDeletedTasks(tt.actor, time.Now().AddDate(0, 0, -30), tt.dryRun)
+			if tt.wantStatus != 0 {
+				assertDomainErrorStatus(t, err, tt.wantStatus)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, wantResult) {
+				t.Errorf("got %+v, want %+v", result, wantResult)
+			}
+
+			removed := !tt.dryRun
+			if _, err := repo.GetTask("vecs"); (err != nil) != removed {
+				t.Errorf("purged task removed: %v, want %v", err != nil, removed)
+			}
+			for _, id := range []string{"jauns", "nesens"} {
+				if _, err := repo.GetTask(id); err != nil {
+					t.Errorf("task %s was removed: %v", id, err)
+				}
+			}
+			for _, key := range testFileKeys {
+				_, ok := testFiles.objects[key]
+				if wantRemoved := removed && key == "ans1"; ok == wantRemoved {
+					t.Errorf("test file %s removed: %v, want %v", key, !ok, wantRemoved)
+				}
+			}
+			for _, key := range publicFileKeys {
+				_, ok := publicFiles.objects[key]
+				if wantRemoved := removed && key == PdfStatementObjKey("p-new"); ok == wantRemoved {
+					t.Errorf("public file %s removed: %v, want %v", key, !ok, wantRemoved)
+				}
+			}
+			wantEvents := 0
+			if removed {
+				wantEvents = 1
+			}
+			if len(sink.events) != wantEvents {
+				t.Errorf("got %d audit events, want %d", len(sink.events), wantEvents)
+			}
+		})
+	}
+}
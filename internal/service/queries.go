@@ -1,13 +1,68 @@
 package service
 
 import (
+	"time"
+
 	"github.com/programme-lv/tasks-microservice/internal/domain"
 )
 
-func (x *TaskService) GetTask(id string) (*domain.Task, error) {
-	return x.repo.GetTask(id)
+// GetTask returns a task. Deleted tasks and, unless preview is set,
+// tasks that are not publicly visible are reported as not found.
+func (x *TaskService) GetTask(id string, preview bool) (*domain.Task, error) {
+	return getViewableTask(x.repo, id, preview)
+}
+
+// ListTasks lists tasks that are not deleted. Unless preview is set,
+// only publicly visible tasks are listed.
+func (x *TaskService) ListTasks(preview bool) ([]domain.Task, error) {
+	tasks, err := x.repo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	if !preview {
+		return publicTasks(tasks), nil
+	}
+
+	res := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if !task.IsDeleted() {
+			res = append(res, task)
+		}
+	}
+	return res, nil
+}
+
+func publicTasks(tasks []domain.Task) []domain.Task {
+	now := time.Now()
+	res := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.IsPublic(now) {
+			res = append(res, task)
+		}
+	}
+	return res
+}
+
+// getViewableTask returns a task the way GetTask does.
+func getViewableTask(repo TaskRepo, id string, preview bool) (*domain.Task, error) {
+	task, err := getLiveTask(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	if !preview && !task.IsPublic(time.Now()) {
+		return nil, domain.ErrorTaskNotFound(id)
+	}
+	return task, nil
 }
 
-func (x *TaskService) ListTasks() ([]domain.Task, error) {
-	return x.repo.ListTasks()
+// getLiveTask returns a task, reporting deleted tasks as not found.
+func getLiveTask(repo TaskRepo, id string) (*domain.Task, error) {
+	task, err := repo.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if task.IsDeleted() {
+		return nil, domain.ErrorTaskNotFound(id)
+	}
+	return task, nil
 }
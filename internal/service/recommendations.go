@@ -0,0 +1,243 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// Weights of the parts of a recommendation score. Every part is in
+// [0;1], so scores are in [0;1] as well.
+const (
+	tagWeight        = 0.6
+	difficultyWeight = 0.3
+	olympiadWeight   = 0.1
+)
+
+const (
+	defaultRecommendationLimit = 10
+	maxRecommendationLimit     = 50
+)
+
+// RecommendationService ranks public tasks by their metadata. Scores
+// depend only on the tasks, and ties are broken by task id, so the
+// same tasks always produce the same ranking.
+type RecommendationService struct {
+	taskRepo TaskRepo
+}
+
+func NewRecommendationService(taskRepo TaskRepo) *RecommendationService {
+	return &RecommendationService{taskRepo: taskRepo}
+}
+
+type ScoredTask struct {
+	Task  domain.Task
+	Score float64
+}
+
+// SimilarTasks ranks the other public tasks by tag overlap, difficulty
+// proximity and a shared origin olympiad with the given task.
+func (x *RecommendationService) SimilarTasks(id string, limit int) ([]ScoredTask, error) {
+	task, err := getLiveTask(x.taskRepo, id)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsPublic(time.Now()) {
+		return nil, domain.ErrorTaskNotFound(id)
+	}
+
+	tasks, err := x.taskRepo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	scored := []ScoredTask{}
+	for _, candidate := range publicTasks(tasks) {
+		if candidate.GetId() == task.GetId() {
+			continue
+		}
+		score := SimilarityScore(task, &candidate)
+		if score > 0 {
+			scored = append(scored, ScoredTask{Task: candidate, Score: score})
+		}
+	}
+	return topScoredTasks(scored, limit), nil
+}
+
+// RecommendTasks suggests unsolved public tasks that are slightly
+// harder than the solved ones and share their tags. Unknown task ids
+// among the solved ones are ignored.
+func (x *RecommendationService) RecommendTasks(solvedIds []string, limit int) ([]ScoredTask, error) {
+	tasks, err := x.taskRepo.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	public := publicTasks(tasks)
+
+	requested := map[string]bool{}
+	for _, id := range solvedIds {
+		requested[id] = true
+	}
+	solved := []domain.Task{}
+	isSolved := map[string]bool{}
+	for _, task := range public {
+		ids := append([]string{task.GetId()}, task.GetAliasIds()...)
+		for _, id := range ids {
+			if requested[id] {
+				solved = append(solved, task)
+				isSolved[task.GetId()] = true
+				break
+			}
+		}
+	}
+
+	profile := newSolverProfile(solved)
+	scored := []ScoredTask{}
+	for _, candidate := range public {
+		if isSolved[candidate.GetId()] {
+			continue
+		}
+		scored = append(scored, ScoredTask{
+			Task:  candidate,
+			Score: profile.score(&candidate),
+		})
+	}
+	return topScoredTasks(scored, limit), nil
+}
+
+// SimilarityScore rates how similar two tasks are: the Jaccard index
+// of their tags, how close their difficulties are and whether they
+// come from the same olympiad.
+func SimilarityScore(a *domain.Task, b *domain.Task) float64 {
+	tagsA := tagSet(a.GetProblemTags())
+	tagsB := tagSet(b.GetProblemTags())
+	shared := 0
+	for tag := range tagsA {
+		if tagsB[tag] {
+			shared++
+		}
+	}
+	tagScore := 0.0
+	if union := len(tagsA) + len(tagsB) - shared; union > 0 {
+		tagScore = float64(shared) / float64(union)
+	}
+
+	olympiadScore := 0.0
+	if sameOlympiad(a, b) {
+		olympiadScore = 1
+	}
+
+	return tagWeight*tagScore +
+		difficultyWeight*difficultyProximity(float64(a.GetDifficulty()), b.GetDifficulty()) +
+		olympiadWeight*olympiadScore
+}
+
+// solverProfile summarizes the tasks a student has solved.
+type solverProfile struct {
+	targetDifficulty float64
+	tagCounts        map[string]int
+	olympiads        map[string]bool
+	solvedCount      int
+}
+
+// newSolverProfile aims one difficulty level above the average of the
+// solved tasks. Students who have solved nothing start at difficulty 1.
+func newSolverProfile(solved []domain.Task) solverProfile {
+	profile := solverProfile{
+		targetDifficulty: 1,
+		tagCounts:        map[string]int{},
+		olympiads:        map[string]bool{},
+		solvedCount:      len(solved),
+	}
+	if len(solved) == 0 {
+		return profile
+	}
+
+	total := 0
+	for _, task := range solved {
+		total += task.GetDifficulty()
+		for tag := range tagSet(task.GetProblemTags()) {
+			profile.tagCounts[tag]++
+		}
+		if olympiad := olympiadKey(&task); olympiad != "" {
+			profile.olympiads[olympiad] = true
+		}
+	}
+	average := float64(total) / float64(len(solved))
+	profile.targetDifficulty = math.Min(5, average+1)
+	return profile
+}
+
+// score rates a candidate task: how close it is to the target
+// difficulty, how familiar its tags are from the solved tasks and
+// whether the student has solved tasks of its olympiad.
+func (p solverProfile) score(task *domain.Task) float64 {
+	tagScore := 0.0
+	tags := tagSet(task.GetProblemTags())
+	if len(tags) > 0 && p.solvedCount > 0 {
+		familiarity := 0.0
+		for tag := range tags {
+			familiarity += float64(p.tagCounts[tag]) / float64(p.solvedCount)
+		}
+		tagScore = familiarity / float64(len(tags))
+	}
+
+	olympiadScore := 0.0
+	if p.olympiads[olympiadKey(task)] {
+		olympiadScore = 1
+	}
+
+	return tagWeight*tagScore +
+		difficultyWeight*difficultyProximity(p.targetDifficulty, task.GetDifficulty()) +
+		olympiadWeight*olympiadScore
+}
+
+// difficultyProximity is 1 for equal difficulties and 0 for
+// difficulties that are the whole scale apart.
+func difficultyProximity(target float64, difficulty int) float64 {
+	return 1 - math.Abs(target-float64(difficulty))/4
+}
+
+func sameOlympiad(a *domain.Task, b *domain.Task) bool {
+	key := olympiadKey(a)
+	return key != "" && key == olympiadKey(b)
+}
+
+// olympiadKey identifies the origin olympiad of a task by its catalogue
+// id, or by its free-form name for tasks not yet mapped to the catalogue.
+func olympiadKey(task *domain.Task) string {
+	if task.GetOriginOlympiadId() != "" {
+		return task.GetOriginOlympiadId()
+	}
+	return task.GetOriginOlympiad()
+}
+
+func tagSet(tags []string) map[string]bool {
+	res := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		res[tag] = true
+	}
+	return res
+}
+
+// topScoredTasks orders tasks by descending score, then by id,
+// and keeps at most limit of them.
+func topScoredTasks(scored []ScoredTask, limit int) []ScoredTask {
+	if limit <= 0 {
+		limit = defaultRecommendationLimit
+	}
+	limit = min(limit, maxRecommendationLimit)
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Task.GetId() < scored[j].Task.GetId()
+	})
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored
+}
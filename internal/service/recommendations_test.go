@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func newRecommendationTestTask(t *testing.T, id string, difficulty int,
+	tags []string, olympiadId string, olympiad string) *domain.Task {
+	t.Helper()
+	task, err := domain.NewTask(id, id)
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	err = task.SetDifficulty(difficulty)
+	if err != nil {
+		t.Fatalf("failed to set difficulty: %v", err)
+	}
+	task.SetProblemTags(tags)
+	task.SetOriginOlympiadId(olympiadId)
+	task.SetOriginOlympiad(olympiad)
+	return task
+}
+
+func TestSimilarityScore(t *testing.T) {
+	type taskSpec struct {
+		difficulty int
+		tags       []string
+		olympiadId string
+		olympiad   string
+	}
+
+	tests := []struct {
+		name string
+		a    taskSpec
+		b    taskSpec
+		want float64
+	}{
+		{
+			name: "identical",
+			a:    taskSpec{3, []string{"dp", "graphs"}, "lio", ""},
+			b:    taskSpec{3, []string{"graphs", "dp"}, "lio", ""},
+			want: 1,
+		},
+		{
+			name: "nothing in common",
+			a:    taskSpec{1, []string{"dp"}, "lio", ""},
+			b:    taskSpec{5, []string{"graphs"}, "boi", ""},
+			want: 0,
+		},
+		{
+			name: "partial tag overlap",
+			a:    taskSpec{3, []string{"dp", "graphs"}, "", ""},
+			b:    taskSpec{3, []string{"graphs", "math"}, "", ""},
+			want: 0.6/3 + 0.3,
+		},
+		{
+			name: "olympiad matched by name",
+			a:    taskSpec{2, []string{}, "", "LIO 2019"},
+			b:    taskSpec{4, []string{}, "", "LIO 2019"},
+			want: 0.3*0.5 + 0.1,
+		},
+		{
+			name: "no olympiad is not a shared olympiad",
+			a:    taskSpec{2, []string{}, "", ""},
+			b:    taskSpec{2, []string{}, "", ""},
+			want: 0.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newRecommendationTestTask(t, "uzdevums-a", tt.a.difficulty,
+				tt.a.tags, tt.a.olympiadId, tt.a.olympiad)
+			b := newRecommendationTestTask(t, "uzdevums-b", tt.b.difficulty,
+				tt.b.tags, tt.b.olympiadId, tt.b.olympiad)
+			got := SimilarityScore(a, b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got score %g, want %g", got, tt.want)
+			}
+			if reverse := SimilarityScore(b, a); math.Abs(reverse-got) > 1e-9 {
+				t.Errorf("score is not symmetric: %g and %g", got, reverse)
+			}
+		})
+	}
+}
+
+func TestTopScoredTasks(t *testing.T) {
+	scoredTasks := func(scores map[string]float64) []ScoredTask {
+		scored := []ScoredTask{}
+		for id, score := range scores {
+			scored = append(scored, ScoredTask{
+				Task:  *newRecommendationTestTask(t, id, 1, []string{}, "", ""),
+				Score: score,
+			})
+		}
+		return scored
+	}
+	manyScores := map[string]float64{}
+	for i := 0; i < 60; i++ {
+		manyScores[fmt.Sprintf("uzdevums-%02d", i)] = 0.5
+	}
+
+	tests := []struct {
+		name    string
+		scores  map[string]float64
+		limit   int
+		wantIds []string
+	}{
+		{
+			name:    "by score then id",
+			scores:  map[string]float64{"cc": 0.5, "aa": 0.5, "bb": 0.9, "dd": 0.1},
+			limit:   10,
+			wantIds: []string{"bb", "aa", "cc", "dd"},
+		},
+		{
+			name:    "limit",
+			scores:  map[string]float64{"cc": 0.5, "aa": 0.5, "bb": 0.9},
+			limit:   2,
+			wantIds: []string{"bb", "aa"},
+		},
+		{
+			name:    "default limit",
+			scores:  manyScores,
+			limit:   0,
+			wantIds: []string{"uzdevums-00", "uzdevums-01", "uzdevums-02", "uzdevums-03", "uzdevums-04", "uzdevums-05", "uzdevums-06", "uzdevums-07", "uzdevums-08", "uzdevums-09"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, task := range topScoredTasks(scoredTasks(tt.scores), tt.limit) {
+				got = append(got, task.Task.GetId())
+			}
+			if !reflect.DeepEqual(got, tt.wantIds) {
+				t.Errorf("got %q, want %q", got, tt.wantIds)
+			}
+		})
+	}
+
+	if got := len(topScoredTasks(scoredTasks(manyScores), 100)); got != maxRecommendationLimit {
+		t.Errorf("got %d tasks, want at most %d", got, maxRecommendationLimit)
+	}
+}
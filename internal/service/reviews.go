@@ -0,0 +1,173 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type ReviewRepo interface {
+	GetReview(taskId string, id string) (*domain.Review, error)
+	ListTaskReviews(taskId string) ([]domain.Review, error)
+	SaveReview(review *domain.Review) error
+}
+
+type ReviewService struct {
+	repo     ReviewRepo
+	taskRepo TaskRepo
+	audit    *AuditService
+}
+
+func NewReviewService(repo ReviewRepo, taskRepo TaskRepo, audit *AuditService) *ReviewService {
+	return &ReviewService{repo: repo, taskRepo: taskRepo, audit: audit}
+}
+
+// SubmitForReview moves a draft task into review and opens
+// a review of its resulting revision.
+func (x *ReviewService) SubmitForReview(actor *auth.Identity, taskId string) (*domain.Review, error) {
+	task, err := getLiveTask(x.taskRepo, taskId)
+	if err != nil {
+		return nil, err
+	}
+	err = authorizeTaskEdit(actor, task)
+	if err != nil {
+		return nil, err
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	err = task.TransitionTo(domain.TaskStateReview)
+	if err != nil {
+		return nil, err
+	}
+	err = x.taskRepo.SaveTask(task)
+	if err != nil {
+		return nil, err
+	}
+	change.record(domain.AuditActionUpdate, task, "")
+
+	id, err := newReviewId()
+	if err != nil {
+		return nil, err
+	}
+	review := domain.NewReview(id, taskId, task.GetRevision(), actor.Subject, time.Now().UTC())
+	err = x.repo.SaveReview(review)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (x *ReviewService) ListTaskReviews(taskId string) ([]domain.Review, error) {
+	return x.repo.ListTaskReviews(taskId)
+}
+
+func (x *ReviewService) GetReview(taskId string, reviewId string) (*domain.Review, error) {
+	return x.repo.GetReview(taskId, reviewId)
+}
+
+// AddComment comments on a review. The anchor is checked against the
+// reviewed revision of the task.
+func (x *ReviewService) AddComment(actor *auth.Identity, taskId string, reviewId string,
+	body string, anchor domain.ReviewAnchor) (*domain.Review, error) {
+	review, err := x.GetReview(taskId, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	task, err := x.taskRepo.GetTaskRevision(taskId, review.GetRevision())
+	if err != nil {
+		return nil, err
+	}
+	if !actor.HasRole(auth.RoleReviewer) {
+		err = authorizeTaskEdit(actor, task)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = review.AddComment(domain.ReviewComment{
+		Author:    actor.Subject,
+		Body:      body,
+		Anchor:    anchor,
+		CreatedAt: time.Now().UTC(),
+	}, task)
+	if err != nil {
+		return nil, err
+	}
+
+	err = x.repo.SaveReview(review)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DecideReview approves the reviewed revision or requests changes,
+// which returns the task to draft.
+func (x *ReviewService) DecideReview(actor *auth.Identity, taskId string, reviewId string,
+	decision domain.ReviewStatus) (*domain.Review, error) {
+	if !actor.HasRole(auth.RoleReviewer) {
+		return nil, domain.ErrorForbidden()
+	}
+	review, err := x.GetReview(taskId, reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = review.Decide(decision, actor.Subject, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if decision == domain.ReviewChangesRequested {
+		task, err := getLiveTask(x.taskRepo, taskId)
+		if err != nil {
+			return nil, err
+		}
+		if task.GetState() == domain.TaskStateReview {
+			change := x.audit.beginTaskChange(actor, task)
+			err = task.TransitionTo(domain.TaskStateDraft)
+			if err != nil {
+				return nil, err
+			}
+			err = x.taskRepo.SaveTask(task)
+			if err != nil {
+				return nil, err
+			}
+			change.record(domain.AuditActionUpdate, task, "")
+		}
+	}
+
+	err = x.repo.SaveReview(review)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// isRevisionApproved reports whether a review approved the current
+// revision of the task.
+func isRevisionApproved(reviews ReviewRepo, task *domain.Task) (bool, error) {
+	taskReviews, err := reviews.ListTaskReviews(task.GetId())
+	if err != nil {
+		return false, err
+	}
+	for _, review := range taskReviews {
+		if review.IsApprovalOf(task.GetId(), task.GetRevision()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newReviewId() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate review id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -6,13 +6,21 @@ import (
 
 type TaskRepo interface {
 	GetTask(id string) (*domain.Task, error)
+	GetTaskRevision(id string, revision int) (*domain.Task, error)
 	ListTasks() ([]domain.Task, error)
+	ListAllRevisions() ([]domain.Task, error)
+	SaveTask(task *domain.Task) error
+	SaveTasks(tasks []*domain.Task) []error
+	RenameTask(oldId string, task *domain.Task) error
+	DeleteTask(id string) error
 }
 
 type TaskService struct {
-	repo TaskRepo
+	repo    TaskRepo
+	reviews ReviewRepo
+	audit   *AuditService
 }
 
-func NewTaskService(repo TaskRepo) *TaskService {
-	return &TaskService{repo: repo}
+func NewTaskService(repo TaskRepo, reviews ReviewRepo, audit *AuditService) *TaskService {
+	return &TaskService{repo: repo, reviews: reviews, audit: audit}
 }
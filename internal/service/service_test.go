@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// memTaskRepo is an in-memory TaskRepo. Like the DynamoDB repo it keeps
+// every saved revision and resolves alias ids to the current task.
+type memTaskRepo struct {
+	tasks     map[string]domain.Task
+	aliases   map[string]string
+	revisions []domain.Task
+}
+
+func newMemTaskRepo(tasks ...*domain.Task) *memTaskRepo {
+	repo := &memTaskRepo{
+		tasks:     map[string]domain.Task{},
+		aliases:   map[string]string{},
+		revisions: []domain.Task{},
+	}
+	for _, task := range tasks {
+		err := repo.SaveTask(task)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return repo
+}
+
+func (r *memTaskRepo) GetTask(id string) (*domain.Task, error) {
+	if aliasOf, ok := r.aliases[id]; ok {
+		id = aliasOf
+	}
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, domain.ErrorTaskNotFound(id)
+	}
+	return &task, nil
+}
+
+func (r *memTaskRepo) GetTaskRevision(id string, revision int) (*domain.Task, error) {
+	current, err := r.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, stored := range r.revisions {
+		if stored.GetRevision() == revision &&
+			(stored.GetId() == current.GetId() || current.HasAlias(stored.GetId())) {
+			return &stored, nil
+		}
+	}
+	return nil, domain.ErrorTaskRevisionNotFound(id, revision)
+}
+
+func (r *memTaskRepo) ListTasks() ([]domain.Task, error) {
+	tasks := []domain.Task{}
+	for _, task := range r.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].GetId() < tasks[j].GetId()
+	})
+	return tasks, nil
+}
+
+func (r *memTaskRepo) ListAllRevisions() ([]domain.Task, error) {
+	return append([]domain.Task{}, r.revisions...), nil
+}
+
+func (r *memTaskRepo) SaveTask(task *domain.Task) error {
+	if _, ok := r.aliases[task.GetId()]; ok {
+		return domain.ErrorTaskIdTaken(task.GetId())
+	}
+	if stored, ok := r.tasks[task.GetId()]; ok && stored.GetRevision() != task.GetRevision() {
+		return domain.ErrorTaskModifiedConcurrently(task.GetId())
+	}
+	task.SetRevision(task.GetRevision() + 1)
+	r.tasks[task.GetId()] = *task
+	r.revisions = append(r.revisions, *task)
+	return nil
+}
+
+func (r *memTaskRepo) SaveTasks(tasks []*domain.Task) []error {
+	errs := make([]error, len(tasks))
+	for i, task := range tasks {
+		errs[i] = r.SaveTask(task)
+	}
+	return errs
+}
+
+func (r *memTaskRepo) RenameTask(oldId string, task *domain.Task) error {
+	if _, ok := r.tasks[task.GetId()]; ok {
+		return domain.ErrorTaskIdTaken(task.GetId())
+	}
+	if aliasOf, ok := r.aliases[task.GetId()]; ok && aliasOf != oldId {
+		return domain.ErrorTaskIdTaken(task.GetId())
+	}
+	stored, ok := r.tasks[oldId]
+	if !ok || stored.GetRevision() != task.GetRevision() {
+		return domain.ErrorTaskModifiedConcurrently(oldId)
+	}
+
+	delete(r.tasks, oldId)
+	delete(r.aliases, task.GetId())
+	for _, aliasId := range task.GetAliasIds() {
+		r.aliases[aliasId] = task.GetId()
+	}
+	task.SetRevision(task.GetRevision() + 1)
+	r.tasks[task.GetId()] = *task
+	r.revisions = append(r.revisions, *task)
+	return nil
+}
+
+func (r *memTaskRepo) DeleteTask(id string) error {
+	task, err := r.GetTask(id)
+	if err != nil {
+		return err
+	}
+	ids := map[string]bool{task.GetId(): true}
+	for _, aliasId := range task.GetAliasIds() {
+		ids[aliasId] = true
+		delete(r.aliases, aliasId)
+	}
+	delete(r.tasks, task.GetId())
+
+	kept := []domain.Task{}
+	for _, revision := range r.revisions {
+		if !ids[revision.GetId()] {
+			kept = append(kept, revision)
+		}
+	}
+	r.revisions = kept
+	return nil
+}
+
+// memBlobStore is an in-memory BlobStore.
+type memBlobStore struct {
+	objects map[string][]byte
+}
+
+func newMemBlobStore(keys ...string) *memBlobStore {
+	store := &memBlobStore{objects: map[string][]byte{}}
+	for _, key := range keys {
+		store.objects[key] = []byte(key)
+	}
+	return store
+}
+
+func (s *memBlobStore) GetObject(key string) ([]byte, error) {
+	content, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+	return content, nil
+}
+
+func (s *memBlobStore) PutObject(key string, content []byte, contentType string) error {
+	s.objects[key] = content
+	return nil
+}
+
+func (s *memBlobStore) DeleteObject(key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+// memAuditSink is an in-memory AuditSink.
+type memAuditSink struct {
+	events []domain.AuditEvent
+}
+
+func (s *memAuditSink) RecordEvent(event domain.AuditEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memAuditSink) ListTaskEvents(taskId string) ([]domain.AuditEvent, error) {
+	events := []domain.AuditEvent{}
+	for _, event := range s.events {
+		if event.TaskId == taskId {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// assertDomainErrorStatus fails the test unless err is a domain error
+// with the status code, or nil if wantStatus is 0.
+func assertDomainErrorStatus(t *testing.T, err error, wantStatus int) {
+	t.Helper()
+	if wantStatus == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.StatusCode != wantStatus {
+		t.Fatalf("got error %v, want status %d", err, wantStatus)
+	}
+}
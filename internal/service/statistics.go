@@ -0,0 +1,109 @@
+package service
+
+import (
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type StatisticsRepo interface {
+	GetTaskStatistics(taskId string) (*domain.TaskStatistics, error) // nil if none were reported
+	ListTaskStatistics() ([]domain.TaskStatistics, error)
+	SaveTaskStatistics(stats *domain.TaskStatistics) error
+}
+
+type StatisticsService struct {
+	repo      StatisticsRepo
+	taskRepo  TaskRepo
+	estimator *DifficultyEstimator
+}
+
+func NewStatisticsService(repo StatisticsRepo, taskRepo TaskRepo,
+	estimator *DifficultyEstimator) *StatisticsService {
+	return &StatisticsService{repo: repo, taskRepo: taskRepo, estimator: estimator}
+}
+
+// TaskStatisticsReport are the submission aggregates of a task as
+// reported by the judge.
+type TaskStatisticsReport struct {
+	TaskId       string
+	Attempts     int
+	Solves       int
+	AvgBestScore float64
+}
+
+// IngestStatistics stores the statistics reported by the judge,
+// replacing earlier reports. All reports are validated before any is
+// stored. Reports of unknown tasks are skipped and their ids returned.
+func (x *StatisticsService) IngestStatistics(actor *auth.Identity,
+	reports []TaskStatisticsReport) ([]string, error) {
+	if actor == nil {
+		return nil, domain.ErrorAuthenticationRequired()
+	}
+	if !actor.HasRole(auth.RoleJudge) {
+		return nil, domain.ErrorForbidden()
+	}
+
+	now := time.Now()
+	unknownTaskIds := []string{}
+	validated := []*domain.TaskStatistics{}
+	for _, report := range reports {
+		task, err := getLiveTask(x.taskRepo, report.TaskId)
+		if isNotFound(err) {
+			unknownTaskIds = append(unknownTaskIds, report.TaskId)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		stats, err := domain.NewTaskStatistics(task.GetId(), report.Attempts,
+			report.Solves, report.AvgBestScore, now)
+		if err != nil {
+			return nil, err
+		}
+		validated = append(validated, stats)
+	}
+
+	for _, stats := range validated {
+		err := x.repo.SaveTaskStatistics(stats)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return unknownTaskIds, nil
+}
+
+type DifficultyEstimate struct {
+	Statistics *domain.TaskStatistics
+	Proposed   int // 0 if there are too few attempts to propose one
+}
+
+// GetDifficultyEstimate returns the difficulty proposed for a task,
+// or nil if the judge has not reported statistics of the task.
+func (x *StatisticsService) GetDifficultyEstimate(taskId string) (*DifficultyEstimate, error) {
+	stats, err := x.repo.GetTaskStatistics(taskId)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+	proposed, _ := x.estimator.Estimate(stats)
+	return &DifficultyEstimate{Statistics: stats, Proposed: proposed}, nil
+}
+
+// ListDifficultyEstimates returns the difficulties proposed for all
+// tasks with reported statistics, keyed by task id.
+func (x *StatisticsService) ListDifficultyEstimates() (map[string]DifficultyEstimate, error) {
+	allStats, err := x.repo.ListTaskStatistics()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]DifficultyEstimate, len(allStats))
+	for _, stats := range allStats {
+		stats := stats
+		proposed, _ := x.estimator.Estimate(&stats)
+		res[stats.GetTaskId()] = DifficultyEstimate{Statistics: &stats, Proposed: proposed}
+	}
+	return res, nil
+}
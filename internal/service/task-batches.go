@@ -0,0 +1,174 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// maxBatchTasks bounds the number of distinct tasks in one batch.
+const maxBatchTasks = 500
+
+// TaskPatch is a partial update of task metadata.
+// Fields that are not set are left unchanged.
+type TaskPatch struct {
+	AddTags        []string
+	RemoveTags     []string
+	Difficulty     *int
+	OriginOlympiad *string
+	State          *domain.TaskState
+}
+
+// TaskBatchOperation applies a patch to each of the listed tasks.
+type TaskBatchOperation struct {
+	TaskIds []string
+	Patch   TaskPatch
+}
+
+type TaskBatchResult struct {
+	TaskId string
+	Task   *domain.Task // the saved task, nil if Err is set
+	Err    error
+}
+
+// batchEntry is a task of a batch together with its pending change.
+type batchEntry struct {
+	result       *TaskBatchResult
+	task         *domain.Task
+	initialState domain.TaskState
+	change       *taskChange
+	edited       bool // a patch changed other fields than the state
+}
+
+// BatchUpdateTasks applies the operations in order in a single pass.
+// Every task is loaded once and patched by all operations that list it,
+// and only the tasks that all their patches apply to are saved. A task's
+// state may not be changed in a batch that also edits it, as an approval
+// covers the revision before the edits. There is a result for every
+// distinct task, in the order the ids first appear.
+func (x *TaskService) BatchUpdateTasks(actor *auth.Identity,
+	operations []TaskBatchOperation) ([]TaskBatchResult, error) {
+	if actor == nil {
+		return nil, domain.ErrorAuthenticationRequired()
+	}
+
+	entries := []*batchEntry{}
+	entriesById := map[string]*batchEntry{} // by requested and by current task id
+	for _, operation := range operations {
+		for _, id := range operation.TaskIds {
+			entry, ok := entriesById[id]
+			if !ok {
+				if len(entries) == maxBatchTasks {
+					return nil, domain.ErrorTaskBatchTooLarge(maxBatchTasks)
+				}
+				entry = x.loadBatchEntry(actor, id, entriesById)
+				if entry.result.TaskId == id {
+					entries = append(entries, entry)
+				}
+				entriesById[id] = entry
+			}
+			if entry.result.Err != nil {
+				continue
+			}
+			entry.result.Err = x.applyTaskPatch(entry, operation.Patch)
+		}
+	}
+
+	valid := []*batchEntry{}
+	for _, entry := range entries {
+		if entry.result.Err == nil {
+			valid = append(valid, entry)
+		}
+	}
+
+	tasks := make([]*domain.Task, 0, len(valid))
+	for _, entry := range valid {
+		tasks = append(tasks, entry.task)
+	}
+	for i, err := range x.repo.SaveTasks(tasks) {
+		entry := valid[i]
+		if err != nil {
+			entry.result.Err = err
+			continue
+		}
+		entry.result.Task = entry.task
+
+		action := domain.AuditActionUpdate
+		if entry.task.GetState() == domain.TaskStatePublished &&
+			entry.initialState != domain.TaskStatePublished {
+			action = domain.AuditActionPublish
+		}
+		entry.change.record(action, entry.task, "")
+	}
+
+	res := make([]TaskBatchResult, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, *entry.result)
+	}
+	return res, nil
+}
+
+// loadBatchEntry loads a task of a batch. An alias of a task that is
+// already in the batch shares the entry of that task.
+func (x *TaskService) loadBatchEntry(actor *auth.Identity, id string,
+	entriesById map[string]*batchEntry) *batchEntry {
+	entry := &batchEntry{result: &TaskBatchResult{TaskId: id}}
+
+	task, err := getLiveTask(x.repo, id)
+	if err != nil {
+		entry.result.Err = err
+		return entry
+	}
+	if existing, ok := entriesById[task.GetId()]; ok {
+		return existing
+	}
+	entriesById[task.GetId()] = entry
+
+	err = authorizeTaskEdit(actor, task)
+	if err != nil {
+		entry.result.Err = err
+		return entry
+	}
+
+	entry.task = task
+	entry.initialState = task.GetState()
+	entry.change = x.audit.beginTaskChange(actor, task)
+	return entry
+}
+
+func (x *TaskService) applyTaskPatch(entry *batchEntry, patch TaskPatch) error {
+	task := entry.task
+	if len(patch.AddTags) > 0 || len(patch.RemoveTags) > 0 ||
+		patch.Difficulty != nil || patch.OriginOlympiad != nil {
+		entry.edited = true
+	}
+	if entry.edited && (task.GetState() != entry.initialState ||
+		patch.State != nil && *patch.State != task.GetState()) {
+		return domain.ErrorTaskBatchMixesStateAndEdits()
+	}
+
+	for _, tag := range patch.AddTags {
+		err := task.AddProblemTag(tag)
+		if err != nil {
+			return err
+		}
+	}
+	for _, tag := range patch.RemoveTags {
+		task.RemoveProblemTag(tag)
+	}
+	if patch.Difficulty != nil {
+		err := task.SetDifficulty(*patch.Difficulty)
+		if err != nil {
+			return err
+		}
+	}
+	if patch.OriginOlympiad != nil {
+		task.SetOriginOlympiad(*patch.OriginOlympiad)
+	}
+	if patch.State != nil {
+		err := x.transitionTask(task, *patch.State)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
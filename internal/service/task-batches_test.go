@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestBatchUpdateTasks(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	owner := &auth.Identity{Subject: "owner", Roles: []auth.Role{auth.RoleAuthor}}
+	difficulty := func(d int) *int { return &d }
+	state := func(s domain.TaskState) *domain.TaskState { return &s }
+
+	tests := []struct {
+		name       string
+		actor      *auth.Identity
+		operations []TaskBatchOperation
+		wantStatus map[string]int // by task id, 0 if saved
+	}{
+		{
+			name:  "edits",
+			actor: admin,
+			operations: []TaskBatchOperation{
+				{TaskIds: []string{"kvadrati", "trijsturi"}, Patch: TaskPatch{AddTags: []string{"dp"}}},
+				{TaskIds: []string{"kvadrati"}, Patch: TaskPatch{Difficulty: difficulty(4)}},
+			},
+			wantStatus: map[string]int{"kvadrati": 0, "trijsturi": 0},
+		},
+		{
+			name:  "invalid patch leaves other tasks alone",
+			actor: admin,
+			operations: []TaskBatchOperation{
+				{TaskIds: []string{"kvadrati"}, Patch: TaskPatch{Difficulty: difficulty(9)}},
+				{TaskIds: []string{"trijsturi"}, Patch: TaskPatch{Difficulty: difficulty(4)}},
+			},
+			wantStatus: map[string]int{"kvadrati": domain.StateConflictErrorCode, "trijsturi": 0},
+		},
+		{
+			name:  "state change mixed with an edit",
+			actor: admin,
+			operations: []TaskBatchOperation{
+				{TaskIds: []string{"kvadrati"}, Patch: TaskPatch{AddTags: []string{"dp"}}},
+				{TaskIds: []string{"kvadrati"}, Patch: TaskPatch{State: state(domain.TaskStateArchived)}},
+			},
+			wantStatus: map[string]int{"kvadrati": domain.UnprocessableEntityErrorCode},
+		},
+		{
+			name:  "state change",
+			actor: admin,
+			operations: []TaskBatchOperation{
+				{TaskIds: []string{"kvadrati"}, Patch: TaskPatch{State: state(domain.TaskStateArchived)}},
+			},
+			wantStatus: map[string]int{"kvadrati": 0},
+		},
+		{
+			name:  "only owned tasks",
+			actor: owner,
+			operations: []TaskBatchOperation{
+				{TaskIds: []string{"kvadrati", "trijsturi"}, Patch: TaskPatch{AddTags: []string{"dp"}}},
+			},
+			wantStatus: map[string]int{"kvadrati": 0, "trijsturi": domain.ForbiddenErrorCode},
+		},
+		{
+			name:  "unknown task",
+			actor: admin,
+			operations: []TaskBatchOperation{
+				{TaskIds: []string{"nav"}, Patch: TaskPatch{AddTags: []string{"dp"}}},
+			},
+			wantStatus: map[string]int{"nav": domain.NotFoundErrorCode},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := []*domain.Task{}
+			for _, id := range []string{"kvadrati", "trijsturi"} {
+				task, err := domain.NewTask(id, id)
+				if err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+				err = task.SetState(domain.TaskStatePublished)
+				if err != nil {
+					t.Fatalf("failed to set state: %v", err)
+				}
+				tasks = append(tasks, task)
+			}
+			tasks[0].SetOwnerIds([]string{"owner"})
+			repo := newMemTaskRepo(tasks...)
+			srv := NewTaskService(repo, nil, NewAuditService(&memAuditSink{}, repo))
+
+			results, err := srv.BatchUpdateTasks(tt.actor, tt.operations)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantStatus) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.wantStatus))
+			}
+			for _, result := range results {
+				assertDomainErrorStatus(t, result.Err, tt.wantStatus[result.TaskId])
+
+				stored, err := repo.GetTask(result.TaskId)
+				if err != nil {
+					continue
+				}
+				saved := stored.GetRevision() > 1
+				if saved != (result.Err == nil) {
+					t.Errorf("task %s saved: %v, want %v", result.TaskId, saved, result.Err == nil)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// DeleteTask marks a task deleted. Its manifest and blobs are kept, so
+// the task can be restored until it is purged.
+func (x *TaskService) DeleteTask(actor *auth.Identity, id string) error {
+	task, err := getLiveTask(x.repo, id)
+	if err != nil {
+		return err
+	}
+	err = authorizeTaskEdit(actor, task)
+	if err != nil {
+		return err
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	err = task.Delete(time.Now())
+	if err != nil {
+		return err
+	}
+	err = x.repo.SaveTask(task)
+	if err != nil {
+		return err
+	}
+	change.record(domain.AuditActionDelete, task, "")
+	return nil
+}
+
+// RestoreTask brings back a deleted task that has not been purged yet.
+func (x *TaskService) RestoreTask(actor *auth.Identity, id string) (*domain.Task, error) {
+	task, err := x.repo.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	err = authorizeTaskEdit(actor, task)
+	if err != nil {
+		return nil, err
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	err = task.Restore()
+	if err != nil {
+		return nil, err
+	}
+	err = x.repo.SaveTask(task)
+	if err != nil {
+		return nil, err
+	}
+	change.record(domain.AuditActionUpdate, task, "")
+	return task, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/taskdiff"
+)
+
+// DiffTask compares two revisions of a task. A zero to revision means the
+// current revision and a zero from revision the one preceding to. The
+// first revision is compared with an empty task.
+func (x *TaskService) DiffTask(id string, from int, to int) (*taskdiff.Diff, error) {
+	if to == 0 {
+		current, err := x.repo.GetTask(id)
+		if err != nil {
+			return nil, err
+		}
+		to = current.GetRevision()
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	toTask, err := x.repo.GetTaskRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+	fromTask := domain.EmptyTask(toTask.GetId())
+	if from != 0 {
+		fromTask, err = x.repo.GetTaskRevision(id, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return taskdiff.Compare(fromTask, toTask), nil
+}
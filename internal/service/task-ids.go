@@ -0,0 +1,27 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// SuggestTaskId derives an unused task id from a task name. If the id
+// derived from the name is taken by a task or an alias, a numeric
+// suffix is added, e.g. "kvadratu-putekli-2".
+func (x *TaskService) SuggestTaskId(name string) (domain.TaskID, error) {
+	base, err := domain.TaskIDFromName(name)
+	if err != nil {
+		return "", err
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		_, err := x.repo.GetTask(candidate.String())
+		if isNotFound(err) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = base.WithSuffix(n)
+	}
+}
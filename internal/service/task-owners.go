@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// SetTaskOwners replaces the users allowed to edit a task. Tasks stored
+// before ownership was introduced have no owners, so only admins can
+// edit them until owners are assigned, e.g. with taskctl set-owners.
+// Only admins may change owners.
+func (x *TaskService) SetTaskOwners(actor *auth.Identity, id string, ownerIds []string) (*domain.Task, error) {
+	if actor == nil {
+		return nil, domain.ErrorAuthenticationRequired()
+	}
+	if !actor.HasRole(auth.RoleAdmin) {
+		return nil, domain.ErrorForbidden()
+	}
+
+	task, err := getLiveTask(x.repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	task.SetOwnerIds(ownerIds)
+	err = x.repo.SaveTask(task)
+	if err != nil {
+		return nil, err
+	}
+	change.record(domain.AuditActionUpdate, task, "")
+	return task, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// RenameTask changes the id of a task. The old id is kept as an alias,
+// so existing links and submissions still resolve to the task.
+func (x *TaskService) RenameTask(actor *auth.Identity, id string, newId string) (*domain.Task, error) {
+	task, err := getLiveTask(x.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	err = authorizeTaskEdit(actor, task)
+	if err != nil {
+		return nil, err
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	oldId := task.GetId()
+	err = task.Rename(newId)
+	if err != nil {
+		return nil, err
+	}
+	err = x.repo.RenameTask(oldId, task)
+	if err != nil {
+		return nil, err
+	}
+	change.record(domain.AuditActionUpdate, task, "")
+	return task, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestRenameTask(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	author := &auth.Identity{Subject: "author", Roles: []auth.Role{auth.RoleAuthor}}
+
+	tests := []struct {
+		name        string
+		actor       *auth.Identity
+		renames     []string // ids the task is renamed to in turn
+		wantStatus  int      // status of the last rename, 0 if it succeeds
+		wantAliases []string
+	}{
+		{name: "rename", actor: admin, renames: []string{"kvadrati-2"},
+			wantAliases: []string{"kvadrati"}},
+		{name: "rename twice", actor: admin, renames: []string{"kvadrati-2", "kvadrati-3"},
+			wantAliases: []string{"kvadrati", "kvadrati-2"}},
+		{name: "rename back to an alias", actor: admin, renames: []string{"kvadrati-2", "kvadrati"},
+			wantAliases: []string{"kvadrati-2"}},
+		{name: "id of another task", actor: admin, renames: []string{"trijsturi"},
+			wantStatus: domain.StateConflictErrorCode},
+		{name: "unchanged id", actor: admin, renames: []string{"kvadrati"},
+			wantStatus: domain.UnprocessableEntityErrorCode},
+		{name: "invalid id", actor: admin, renames: []string{"Kvadrāti"},
+			wantStatus: domain.UnprocessableEntityErrorCode},
+		{name: "not an owner", actor: author, renames: []string{"kvadrati-2"},
+			wantStatus: domain.ForbiddenErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kvadrati, err := domain.NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			trijsturi, err := domain.NewTask("trijsturi", "Trijstūri")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			repo := newMemTaskRepo(kvadrati, trijsturi)
+			sink := &memAuditSink{}
+			srv := NewTaskService(repo, nil, NewAuditService(sink, repo))
+
+			id := "kvadrati"
+			var renamed *domain.Task
+			for _, newId := range tt.renames {
+				renamed, err = srv.RenameTask(tt.actor, id, newId)
+				if err != nil {
+					break
+				}
+				id = newId
+			}
+			if tt.wantStatus != 0 {
+				assertDomainErrorStatus(t, err, tt.wantStatus)
+				if _, err := repo.GetTask("kvadrati"); err != nil {
+					t.Errorf("failed rename lost the task: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(renamed.GetAliasIds(), tt.wantAliases) {
+				t.Errorf("got aliases %q, want %q", renamed.GetAliasIds(), tt.wantAliases)
+			}
+			for _, alias := range tt.wantAliases {
+				task, err := repo.GetTask(alias)
+				if err != nil || task.GetId() != id {
+					t.Errorf("alias %s does not resolve to %s: %v", alias, id, err)
+				}
+			}
+			if len(sink.events) != len(tt.renames) {
+				t.Errorf("got %d audit events, want %d", len(sink.events), len(tt.renames))
+			}
+		})
+	}
+}
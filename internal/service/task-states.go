@@ -0,0 +1,74 @@
+package service
+
+import (
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+// ChangeTaskState moves a task to another lifecycle state. When the task
+// is published, publishAt, if set, replaces the time from which it is
+// publicly visible; otherwise a scheduled publish is kept. Besides the
+// task's editors, reviewers may move tasks that are under review.
+// Tasks enter review only by being submitted for review, and publishing
+// a task under review requires an approved review. Archived tasks return
+// to draft and are reviewed again before being republished.
+func (x *TaskService) ChangeTaskState(actor *auth.Identity, id string,
+	state domain.TaskState, publishAt *time.Time) (*domain.Task, error) {
+	task, err := getLiveTask(x.repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewing := task.GetState() == domain.TaskStateReview && actor.HasRole(auth.RoleReviewer)
+	if !reviewing {
+		err = authorizeTaskEdit(actor, task)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	change := x.audit.beginTaskChange(actor, task)
+	err = x.transitionTask(task, state)
+	if err != nil {
+		return nil, err
+	}
+	if publishAt != nil && state == domain.TaskStatePublished {
+		task.SetPublishAt(publishAt)
+	}
+
+	err = x.repo.SaveTask(task)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.AuditActionUpdate
+	if state == domain.TaskStatePublished {
+		action = domain.AuditActionPublish
+	}
+	change.record(action, task, "")
+	return task, nil
+}
+
+// transitionTask moves the task to the state unless it is already in it.
+// Tasks enter review only by being submitted for review, and publishing
+// requires an approved review of the current revision.
+func (x *TaskService) transitionTask(task *domain.Task, state domain.TaskState) error {
+	if task.GetState() == state {
+		return nil
+	}
+	if state == domain.TaskStateReview {
+		return domain.ErrorReviewSubmissionRequired()
+	}
+	if state == domain.TaskStatePublished {
+		approved, err := isRevisionApproved(x.reviews, task)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			return domain.ErrorReviewApprovalRequired()
+		}
+	}
+	return task.TransitionTo(state)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/auth"
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestChangeTaskStateKeepsScheduledPublish(t *testing.T) {
+	admin := &auth.Identity{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	scheduled := time.Date(2025, 2, 15, 12, 0, 0, 0, time.UTC)
+	other := scheduled.Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		from          domain.TaskState
+		to            domain.TaskState
+		publishAt     *time.Time
+		wantPublishAt *time.Time
+	}{
+		{name: "archive without publish time", from: domain.TaskStatePublished,
+			to: domain.TaskStateArchived, wantPublishAt: &scheduled},
+		{name: "publish time ignored when archiving", from: domain.TaskStatePublished,
+			to: domain.TaskStateArchived, publishAt: &other, wantPublishAt: &scheduled},
+		{name: "published again without publish time", from: domain.TaskStatePublished,
+			to: domain.TaskStatePublished, wantPublishAt: &scheduled},
+		{name: "publish time replaced", from: domain.TaskStatePublished,
+			to: domain.TaskStatePublished, publishAt: &other, wantPublishAt: &other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := domain.NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			err = task.SetState(tt.from)
+			if err != nil {
+				t.Fatalf("failed to set state: %v", err)
+			}
+			task.SetPublishAt(&scheduled)
+
+			repo := newMemTaskRepo(task)
+			srv := NewTaskService(repo, nil, NewAuditService(&memAuditSink{}, repo))
+			changed, err := srv.ChangeTaskState(admin, "kvadrati", tt.to, tt.publishAt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed.GetState() != tt.to {
+				t.Errorf("got state %q, want %q", changed.GetState(), tt.to)
+			}
+			if got := changed.GetPublishAt(); got == nil || !got.Equal(*tt.wantPublishAt) {
+				t.Errorf("got publish time %v, want %v", got, tt.wantPublishAt)
+			}
+		})
+	}
+}
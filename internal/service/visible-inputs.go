@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+type VisibleInputService struct {
+	taskRepo  TaskRepo
+	testFiles BlobStore // test inputs and answers by their sha256
+}
+
+func NewVisibleInputService(taskRepo TaskRepo, testFiles BlobStore) *VisibleInputService {
+	return &VisibleInputService{taskRepo: taskRepo, testFiles: testFiles}
+}
+
+// GetVisibleInputs returns the visible inputs of a subtask. Tasks are
+// looked up like TaskService.GetTask, so preview allows drafts.
+func (x *VisibleInputService) GetVisibleInputs(taskId string, subtask int, preview bool) (
+	[]domain.VisibleInput, error) {
+	task, err := getViewableTask(x.taskRepo, taskId, preview)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, ok := task.GetVisibleInputs(subtask)
+	if !ok {
+		return nil, domain.ErrorSubtaskHasNoVisibleInputs(subtask)
+	}
+	return inputs, nil
+}
+
+// ReadVisibleInput returns the content of an inline or blob-referenced input.
+func (x *VisibleInputService) ReadVisibleInput(input domain.VisibleInput) ([]byte, error) {
+	if input.IsInline() {
+		return []byte(*input.Content), nil
+	}
+	return x.testFiles.GetObject(input.Sha256)
+}
+
+// GetOutputOnlyTests returns the tests of an output-only task, whose
+// inputs are downloadable by contestants. Tasks are looked up like
+// TaskService.GetTask, so preview allows drafts.
+func (x *VisibleInputService) GetOutputOnlyTests(taskId string, preview bool) (
+	[]domain.TestSha256Ref, error) {
+	task, err := getViewableTask(x.taskRepo, taskId, preview)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.IsOutputOnly() {
+		return nil, domain.ErrorTaskIsNotOutputOnly()
+	}
+	return task.GetTests(), nil
+}
+
+func (x *VisibleInputService) ReadTestInput(test domain.TestSha256Ref) ([]byte, error) {
+	return x.testFiles.GetObject(test.InputSha256)
+}
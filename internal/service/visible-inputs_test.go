@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+)
+
+func TestGetVisibleInputsPreview(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      domain.TaskState
+		deleted    bool
+		preview    bool
+		wantStatus int // 0 if the inputs are returned
+	}{
+		{name: "published", state: domain.TaskStatePublished},
+		{name: "draft", state: domain.TaskStateDraft, wantStatus: domain.NotFoundErrorCode},
+		{name: "draft previewed", state: domain.TaskStateDraft, preview: true},
+		{name: "deleted previewed", state: domain.TaskStatePublished, deleted: true,
+			preview: true, wantStatus: domain.NotFoundErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := domain.NewTask("kvadrati", "Kvadrāti")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			err = task.SetState(tt.state)
+			if err != nil {
+				t.Fatalf("failed to set state: %v", err)
+			}
+			if tt.deleted {
+				deletedAt := time.Now()
+				task.SetDeletedAt(&deletedAt)
+			}
+			task.AddVisibleInputSubtask(1, []domain.VisibleInput{domain.NewInlineVisibleInput("1 2")})
+			srv := NewVisibleInputService(newMemTaskRepo(task), newMemBlobStore())
+
+			inputs, err := srv.GetVisibleInputs("kvadrati", 1, tt.preview)
+			if tt.wantStatus != 0 {
+				assertDomainErrorStatus(t, err, tt.wantStatus)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(inputs) != 1 {
+				t.Errorf("got %d inputs, want 1", len(inputs))
+			}
+		})
+	}
+}
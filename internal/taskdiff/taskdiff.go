@@ -0,0 +1,325 @@
+// Package taskdiff reports what changed between two revisions of a task.
+package taskdiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/programme-lv/tasks-microservice/internal/domain"
+	"github.com/programme-lv/tasks-microservice/internal/textdiff"
+)
+
+type Diff struct {
+	TaskId       string
+	FromRevision int
+	ToRevision   int
+
+	Fields     []FieldChange
+	Tests      []TestChange
+	TestGroups []TestGroupChange
+	Statements []StatementChange
+}
+
+// FieldChange is a changed scalar field with its formatted values.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+)
+
+// TestChange is an added, removed or modified test. The old hashes are
+// empty for added tests and the new ones for removed tests.
+type TestChange struct {
+	TestId          int64
+	Kind            ChangeKind
+	OldInputSha256  string
+	OldAnswerSha256 string
+	NewInputSha256  string
+	NewAnswerSha256 string
+}
+
+type TestGroupChange struct {
+	GroupId        int
+	Kind           ChangeKind
+	OldPoints      int
+	NewPoints      int
+	AddedTestIds   []int
+	RemovedTestIds []int
+}
+
+// StatementChange is a line-level diff of one section of a markdown
+// statement, e.g. the "input" section of the "lv" statement.
+type StatementChange struct {
+	Language string
+	Section  string
+	Lines    []textdiff.Line
+}
+
+// IsEmpty reports whether the revisions do not differ.
+func (d *Diff) IsEmpty() bool {
+	return len(d.Fields) == 0 && len(d.Tests) == 0 &&
+		len(d.TestGroups) == 0 && len(d.Statements) == 0
+}
+
+// Compare computes the changes that turn from into to.
+func Compare(from *domain.Task, to *domain.Task) *Diff {
+	return &Diff{
+		TaskId:       to.GetId(),
+		FromRevision: from.GetRevision(),
+		ToRevision:   to.GetRevision(),
+		Fields:       compareFields(from, to),
+		Tests:        compareTests(from.GetTests(), to.GetTests()),
+		TestGroups:   compareTestGroups(from.GetTestGroups(), to.GetTestGroups()),
+		Statements:   compareStatements(from.GetMarkdownStatements(), to.GetMarkdownStatements()),
+	}
+}
+
+func compareFields(from *domain.Task, to *domain.Task) []FieldChange {
+	fields := func(t *domain.Task) [][2]string {
+		publishAt := ""
+		if t.GetPublishAt() != nil {
+			publishAt = t.GetPublishAt().String()
+		}
+		return [][2]string{
+			{"task_full_name", t.GetTaskFullName()},
+			{"state", string(t.GetState())},
+			{"publish_at", publishAt},
+			{"memory_limit_megabytes", fmt.Sprint(t.GetMemoryLimitMBytes())},
+			{"cpu_time_limit_seconds", fmt.Sprint(t.GetCpuTimeLimitSecs())},
+			{"wall_time_limit_seconds", fmt.Sprint(t.GetWallTimeLimitSecs())},
+			{"stack_limit_megabytes", fmt.Sprint(t.GetStackLimitMBytes())},
+			{"difficulty", fmt.Sprint(t.GetDifficulty())},
+			{"origin_olympiad", t.GetOriginOlympiad()},
+			{"origin_olympiad_id", t.GetOriginOlympiadId()},
+			{"problem_tags", strings.Join(t.GetProblemTags(), ", ")},
+			{"illustration_img", t.GetIllustrationImgObjKey()},
+			{"evaluation_type", string(t.GetEvaluation().Type)},
+		}
+	}
+
+	changes := []FieldChange{}
+	toFields := fields(to)
+	for i, field := range fields(from) {
+		if field[1] != toFields[i][1] {
+			changes = append(changes, FieldChange{
+				Field: field[0],
+				Old:   field[1],
+				New:   toFields[i][1],
+			})
+		}
+	}
+	return changes
+}
+
+func compareTests(from []domain.TestSha256Ref, to []domain.TestSha256Ref) []TestChange {
+	fromById := map[int64]domain.TestSha256Ref{}
+	for _, test := range from {
+		fromById[test.TestId] = test
+	}
+	toById := map[int64]domain.TestSha256Ref{}
+	for _, test := range to {
+		toById[test.TestId] = test
+	}
+
+	changes := []TestChange{}
+	for _, test := range from {
+		if _, ok := toById[test.TestId]; !ok {
+			changes = append(changes, TestChange{
+				TestId:          test.TestId,
+				Kind:            Removed,
+				OldInputSha256:  test.InputSha256,
+				OldAnswerSha256: test.AnswerSha256,
+			})
+		}
+	}
+	for _, test := range to {
+		old, ok := fromById[test.TestId]
+		if !ok {
+			changes = append(changes, TestChange{
+				TestId:          test.TestId,
+				Kind:            Added,
+				NewInputSha256:  test.InputSha256,
+				NewAnswerSha256: test.AnswerSha256,
+			})
+			continue
+		}
+		if old.InputSha256 != test.InputSha256 || old.AnswerSha256 != test.AnswerSha256 {
+			changes = append(changes, TestChange{
+				TestId:          test.TestId,
+				Kind:            Modified,
+				OldInputSha256:  old.InputSha256,
+				OldAnswerSha256: old.AnswerSha256,
+				NewInputSha256:  test.InputSha256,
+				NewAnswerSha256: test.AnswerSha256,
+			})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].TestId < changes[j].TestId
+	})
+	return changes
+}
+
+func compareTestGroups(from []domain.TestGroup, to []domain.TestGroup) []TestGroupChange {
+	fromById := map[int]domain.TestGroup{}
+	for _, group := range from {
+		fromById[group.GroupId] = group
+	}
+	toById := map[int]domain.TestGroup{}
+	for _, group := range to {
+		toById[group.GroupId] = group
+	}
+
+	changes := []TestGroupChange{}
+	for _, group := range from {
+		if _, ok := toById[group.GroupId]; !ok {
+			changes = append(changes, TestGroupChange{
+				GroupId:        group.GroupId,
+				Kind:           Removed,
+				OldPoints:      group.Points,
+				AddedTestIds:   []int{},
+				RemovedTestIds: group.TestIds,
+			})
+		}
+	}
+	for _, group := range to {
+		old, ok := fromById[group.GroupId]
+		if !ok {
+			changes = append(changes, TestGroupChange{
+				GroupId:        group.GroupId,
+				Kind:           Added,
+				NewPoints:      group.Points,
+				AddedTestIds:   group.TestIds,
+				RemovedTestIds: []int{},
+			})
+			continue
+		}
+		added := difference(group.TestIds, old.TestIds)
+		removed := difference(old.TestIds, group.TestIds)
+		if old.Points != group.Points || len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, TestGroupChange{
+				GroupId:        group.GroupId,
+				Kind:           Modified,
+				OldPoints:      old.Points,
+				NewPoints:      group.Points,
+				AddedTestIds:   added,
+				RemovedTestIds: removed,
+			})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].GroupId < changes[j].GroupId
+	})
+	return changes
+}
+
+// difference returns the ids in a that are not in b.
+func difference(a []int, b []int) []int {
+	inB := map[int]bool{}
+	for _, id := range b {
+		inB[id] = true
+	}
+	res := []int{}
+	for _, id := range a {
+		if !inB[id] {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+func compareStatements(from map[string]*domain.MarkdownStatement,
+	to map[string]*domain.MarkdownStatement) []StatementChange {
+	languages := []string{}
+	for language := range from {
+		languages = append(languages, language)
+	}
+	for language := range to {
+		if _, ok := from[language]; !ok {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+
+	changes := []StatementChange{}
+	for _, language := range languages {
+		fromSections := statementSections(from[language])
+		toSections := statementSections(to[language])
+		for i, section := range fromSections {
+			lines := textdiff.Lines(section[1], toSections[i][1])
+			if textdiff.Changed(lines) {
+				changes = append(changes, StatementChange{
+					Language: language,
+					Section:  section[0],
+					Lines:    lines,
+				})
+			}
+		}
+	}
+	return changes
+}
+
+// statementSections returns the named sections of a statement in
+// presentation order. A missing statement has empty sections.
+func statementSections(statement *domain.MarkdownStatement) [][2]string {
+	if statement == nil {
+		statement = &domain.MarkdownStatement{}
+	}
+	optional := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	return [][2]string{
+		{"story", statement.Story},
+		{"input", statement.Input},
+		{"output", statement.Output},
+		{"notes", optional(statement.Notes)},
+		{"scoring", optional(statement.Scoring)},
+	}
+}
+
+// Format renders the diff as text for terminals, with the given number
+// of unchanged context lines around statement changes.
+func (d *Diff) Format(context int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "task %s: revision %d -> %d\n", d.TaskId, d.FromRevision, d.ToRevision)
+
+	for _, field := range d.Fields {
+		fmt.Fprintf(&sb, "%s: %q -> %q\n", field.Field, field.Old, field.New)
+	}
+	for _, test := range d.Tests {
+		switch test.Kind {
+		case Added:
+			fmt.Fprintf(&sb, "test %d added: input %s, answer %s\n",
+				test.TestId, test.NewInputSha256, test.NewAnswerSha256)
+		case Removed:
+			fmt.Fprintf(&sb, "test %d removed: input %s, answer %s\n",
+				test.TestId, test.OldInputSha256, test.OldAnswerSha256)
+		default:
+			fmt.Fprintf(&sb, "test %d modified: input %s -> %s, answer %s -> %s\n",
+				test.TestId, test.OldInputSha256, test.NewInputSha256,
+				test.OldAnswerSha256, test.NewAnswerSha256)
+		}
+	}
+	for _, group := range d.TestGroups {
+		fmt.Fprintf(&sb, "test group %d %s: points %d -> %d, tests +%v -%v\n",
+			group.GroupId, group.Kind, group.OldPoints, group.NewPoints,
+			group.AddedTestIds, group.RemovedTestIds)
+	}
+	for _, statement := range d.Statements {
+		fmt.Fprintf(&sb, "statement %s %s:\n", statement.Language, statement.Section)
+		sb.WriteString(textdiff.Format(statement.Lines, context))
+	}
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+// Package textdiff computes line-level differences between two texts.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpInsert
+	OpDelete
+)
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the edit script that turns a into b using the
+// longest common subsequence of their lines.
+func Lines(a, b string) []Line {
+	aLines := splitLines(a)
+	bLines := splitLines(b)
+
+	// lcs[i][j] is the lcs length of aLines[i:] and bLines[j:]
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	res := []Line{}
+	i, j := 0, 0
+	for i < len(aLines) && j < len(bLines) {
+		switch {
+		case aLines[i] == bLines[j]:
+			res = append(res, Line{Op: OpEqual, Text: aLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, Line{Op: OpDelete, Text: aLines[i]})
+			i++
+		default:
+			res = append(res, Line{Op: OpInsert, Text: bLines[j]})
+			j++
+		}
+	}
+	for ; i < len(aLines); i++ {
+		res = append(res, Line{Op: OpDelete, Text: aLines[i]})
+	}
+	for ; j < len(bLines); j++ {
+		res = append(res, Line{Op: OpInsert, Text: bLines[j]})
+	}
+	return res
+}
+
+// Changed reports whether the edit script contains any insertions or deletions.
+func Changed(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders the edit script with "+", "-" and " " line prefixes,
+// keeping only the given number of unchanged context lines around changes.
+func Format(lines []Line, context int) string {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == OpEqual {
+			continue
+		}
+		for k := max(0, i-context); k <= min(len(lines)-1, i+context); k++ {
+			keep[k] = true
+		}
+	}
+
+	var sb strings.Builder
+	skipped := false
+	for i, line := range lines {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			sb.WriteString("@@\n")
+			skipped = false
+		}
+		prefix := " "
+		switch line.Op {
+		case OpInsert:
+			prefix = "+"
+		case OpDelete:
+			prefix = "-"
+		}
+		fmt.Fprintf(&sb, "%s%s\n", prefix, line.Text)
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}